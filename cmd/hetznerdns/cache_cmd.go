@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the local zone cache",
+	Long: `Manage the local cache of zone IDs.
+
+Commands that take a zone name look up its ID once and keep it in
+~/.config/hetznerdns/zone-cache.json for an hour. Creating or deleting a zone
+with hetznerdns updates the cache; use --no-cache or 'cache clear' after
+changing zones elsewhere.`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached zone IDs",
+	Long:  `Remove all cached zone IDs, so they are looked up from the API again.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := clearZoneCache(); err != nil {
+			return withContext("Error clearing cache", err)
+		}
+		fmt.Println("Zone cache cleared.")
+		return nil
+	},
+}
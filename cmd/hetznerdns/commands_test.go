@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/shotgundd/hetznerdns/pkg/api"
+	"github.com/shotgundd/hetznerdns/pkg/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// fakeClient is an in-memory stand-in for the API. Methods it does not
+// implement panic through the nil embedded interface.
+type fakeClient struct {
+	api.DNSClient
+	zones   []api.Zone
+	records []api.Record
+}
+
+func (f *fakeClient) GetZonesContext(ctx context.Context) ([]api.Zone, error) {
+	return f.zones, nil
+}
+
+func (f *fakeClient) GetZoneIDByNameContext(ctx context.Context, name string) (string, error) {
+	for _, zone := range f.zones {
+		if zone.Name == name {
+			return zone.ID, nil
+		}
+	}
+	return "", api.ErrNotFound
+}
+
+func (f *fakeClient) GetRecordsContext(ctx context.Context, zoneID string) ([]api.Record, error) {
+	var records []api.Record
+	for _, record := range f.records {
+		if record.ZoneID == zoneID {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func (f *fakeClient) CreateRecordContext(ctx context.Context, record api.Record) (*api.Record, error) {
+	record.ID = fmt.Sprintf("record%d", len(f.records)+1)
+	f.records = append(f.records, record)
+	return &record, nil
+}
+
+// executeCommand runs the CLI in-process against client and returns what it
+// printed to stdout and the error of the command
+func executeCommand(t *testing.T, client api.DNSClient, args ...string) (string, error) {
+	t.Helper()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("HETZNER_DNS_API_TOKEN", "test-token")
+
+	origNewClient := newClient
+	newClient = func(cfg *config.Config, extra ...api.Option) api.DNSClient { return client }
+	t.Cleanup(func() {
+		newClient = origNewClient
+		resetFlags(rootCmd)
+	})
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	origStdout := os.Stdout
+	os.Stdout = w
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+
+	rootCmd.SetArgs(args)
+	err = execute(context.Background())
+	w.Close()
+	os.Stdout = origStdout
+	return <-output, err
+}
+
+// resetFlags restores the defaults of the flags of cmd and its subcommands,
+// which keep their values between in-process runs
+func resetFlags(cmd *cobra.Command) {
+	reset := func(flag *pflag.Flag) {
+		// Setting a slice flag appends to it, and its default reads "[]"
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			slice.Replace(nil)
+		} else {
+			flag.Value.Set(flag.DefValue)
+		}
+		flag.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, sub := range cmd.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// mustExecuteCommand is executeCommand for commands expected to succeed
+func mustExecuteCommand(t *testing.T, client api.DNSClient, args ...string) string {
+	t.Helper()
+
+	stdout, err := executeCommand(t, client, args...)
+	if err != nil {
+		t.Fatalf("Command %v failed: %v\nOutput: %s", args, err, stdout)
+	}
+	return stdout
+}
+
+func TestZoneCommands(t *testing.T) {
+	client := &fakeClient{zones: []api.Zone{{ID: "zone1", Name: "example.com", TTL: 86400}}}
+
+	stdout := mustExecuteCommand(t, client, "zone", "list")
+	if !strings.Contains(stdout, "zone1") || !strings.Contains(stdout, "example.com") {
+		t.Errorf("Expected zone list output, got: %s", stdout)
+	}
+}
+
+func TestRecordCommands(t *testing.T) {
+	client := &fakeClient{zones: []api.Zone{{ID: "zone1", Name: "example.com"}}}
+
+	mustExecuteCommand(t, client, "record", "create", "--zone", "example.com", "--type", "A", "--name", "www", "--value", "192.0.2.1")
+	if len(client.records) != 1 || client.records[0].ZoneID != "zone1" {
+		t.Fatalf("Expected a record in zone1, got %+v", client.records)
+	}
+
+	stdout := mustExecuteCommand(t, client, "record", "list", "--zone", "example.com")
+	if !strings.Contains(stdout, "record1") || !strings.Contains(stdout, "192.0.2.1") {
+		t.Errorf("Expected record list output, got: %s", stdout)
+	}
+}
+
+func TestCommandErrors(t *testing.T) {
+	client := &fakeClient{zones: []api.Zone{{ID: "zone1", Name: "example.com"}}}
+
+	tests := []struct {
+		args     []string
+		exitCode int
+	}{
+		{[]string{"record", "create", "--zone", "example.com", "--type", "MX", "--name", "@", "--value", "mail.example.com"}, exitValidation},
+		{[]string{"record", "list", "--zone", "example.org"}, exitNotFound},
+		{[]string{"record", "list", "--zone", "example.com", "--modified-since", "yesterday"}, exitUsage},
+		{[]string{"record", "list", "--no-such-flag"}, exitUsage},
+		{[]string{"zone", "get"}, exitUsage},
+		{[]string{"zone", "list", "-o", "xml"}, exitUsage},
+		{[]string{"no-such-command"}, exitUsage},
+	}
+
+	for _, tt := range tests {
+		stdout, err := executeCommand(t, client, tt.args...)
+		if err == nil {
+			t.Errorf("%v: expected an error", tt.args)
+			continue
+		}
+		if code := exitCode(err); code != tt.exitCode {
+			t.Errorf("%v: expected exit code %d, got %d (%v)", tt.args, tt.exitCode, code, err)
+		}
+		if stdout != "" {
+			t.Errorf("%v: expected no output on stdout, got: %s", tt.args, stdout)
+		}
+	}
+}
+
+func TestNotFoundHints(t *testing.T) {
+	tests := []struct {
+		cmd  *cobra.Command
+		want string
+	}{
+		{zoneListCmd, "hetznerdns zone list"},
+		{recordListCmd, "hetznerdns record list"},
+		{primaryServerRemoveCmd, "hetznerdns primary-server list"},
+		{nil, "Check the IDs and names"},
+	}
+
+	for _, tt := range tests {
+		if hint := errorHint(api.ErrNotFound, tt.cmd); !strings.Contains(hint, tt.want) {
+			t.Errorf("Expected the hint for %v to contain %q, got: %s", tt.cmd, tt.want, hint)
+		}
+	}
+
+	// The hint follows the command that failed
+	client := &fakeClient{zones: []api.Zone{{ID: "zone1", Name: "example.com"}}}
+	_, err := executeCommand(t, client, "record", "list", "--zone", "example.org")
+	if hint := errorHint(err, runningCommand); !strings.Contains(hint, "hetznerdns record list") {
+		t.Errorf("Expected a record hint, got: %s", hint)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/shotgundd/hetznerdns/pkg/config"
 	"github.com/spf13/cobra"
@@ -26,7 +27,7 @@ var configSetCmd = &cobra.Command{
 	Use:   "set",
 	Short: "Set configuration values",
 	Long:  `Set configuration values like API token.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Check if api-token flag is provided
 		apiToken, _ := cmd.Flags().GetString("api-token")
 
@@ -37,25 +38,24 @@ var configSetCmd = &cobra.Command{
 				apiToken = args[1]
 			} else {
 				// Interactive mode
-				fmt.Print("Enter your Hetzner DNS API token: ")
+				fmt.Fprint(os.Stderr, "Enter your Hetzner DNS API token: ")
 				fmt.Scanln(&apiToken)
 			}
 		}
 
 		cfg, err := config.LoadConfig()
 		if err != nil {
-			fmt.Printf("Error loading config: %v\n", err)
-			return
+			return withContext("Error loading config", err)
 		}
 
 		cfg.APIToken = apiToken
 
 		if err := config.SaveConfig(cfg); err != nil {
-			fmt.Printf("Error saving config: %v\n", err)
-			return
+			return withContext("Error saving config", err)
 		}
 
 		fmt.Println("Configuration saved successfully.")
+		return nil
 	},
 }
 
@@ -63,11 +63,10 @@ var configShowCmd = &cobra.Command{
 	Use:   "show",
 	Short: "Show current configuration",
 	Long:  `Display the current configuration values.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		cfg, err := config.LoadConfig()
 		if err != nil {
-			fmt.Printf("Error loading config: %v\n", err)
-			return
+			return withContext("Error loading config", err)
 		}
 
 		if cfg.APIToken == "" {
@@ -82,5 +81,10 @@ var configShowCmd = &cobra.Command{
 				fmt.Println("API token: ********")
 			}
 		}
+
+		if cfg.Endpoint != "" {
+			fmt.Printf("API endpoint: %s\n", cfg.Endpoint)
+		}
+		return nil
 	},
 }
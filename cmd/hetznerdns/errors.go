@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/shotgundd/hetznerdns/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes used when a command fails
+const (
+	exitGeneric     = 1
+	exitUsage       = 2
+	exitAuth        = 3
+	exitNotFound    = 4
+	exitValidation  = 5
+	exitRateLimited = 6
+)
+
+// errNoToken is returned by commands that need an API token when none is
+// configured. It matches api.ErrUnauthorized, so it exits with exitAuth.
+var errNoToken = fmt.Errorf("%w: API token not set", api.ErrUnauthorized)
+
+// commandError describes what a command was doing when err occurred, e.g.
+// "Error fetching zones"
+type commandError struct {
+	context string
+	err     error
+}
+
+// Error implements the error interface
+func (e *commandError) Error() string {
+	return fmt.Sprintf("%s: %v", e.context, e.err)
+}
+
+// Unwrap returns the underlying error
+func (e *commandError) Unwrap() error {
+	return e.err
+}
+
+// withContext annotates err with what the command was doing
+func withContext(context string, err error) error {
+	return &commandError{context: context, err: err}
+}
+
+// usageError is returned for invalid flags and arguments
+type usageError struct {
+	err error
+}
+
+// Error implements the error interface
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the underlying error
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+// usageErrorf returns a usage error with a formatted message
+func usageErrorf(format string, args ...interface{}) error {
+	return &usageError{err: fmt.Errorf(format, args...)}
+}
+
+// exitCode maps an error to the process exit code
+func exitCode(err error) int {
+	var usageErr *usageError
+	switch {
+	case errors.As(err, &usageErr):
+		return exitUsage
+	case errors.Is(err, api.ErrUnauthorized), errors.Is(err, api.ErrForbidden):
+		return exitAuth
+	case errors.Is(err, api.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, api.ErrValidation):
+		return exitValidation
+	case errors.Is(err, api.ErrRateLimited):
+		return exitRateLimited
+	default:
+		return exitGeneric
+	}
+}
+
+// errorHint returns advice for the user on how to resolve err returned by
+// cmd, if any. cmd is nil if the command did not start.
+func errorHint(err error, cmd *cobra.Command) string {
+	var valueErr *api.RecordValueError
+	var usageErr *usageError
+	switch {
+	case errors.As(err, &usageErr):
+		return "Run 'hetznerdns --help' for usage."
+	case errors.As(err, &valueErr):
+		return "Fix the record value, or pass --skip-validation to send it to the API unchecked."
+	case errors.Is(err, errNoToken):
+		return "Run 'hetznerdns config set' or set HETZNER_DNS_API_TOKEN to configure your API token."
+	case errors.Is(err, api.ErrUnauthorized):
+		return "The API token is invalid or expired. Run 'hetznerdns config set' to update it."
+	case errors.Is(err, api.ErrForbidden):
+		return "The API token is not allowed to access this resource."
+	case errors.Is(err, api.ErrNotFound):
+		return notFoundHint(cmd)
+	case errors.Is(err, api.ErrValidation):
+		return "The API rejected the request data. Check the record name, type and value."
+	case errors.Is(err, api.ErrRateLimited):
+		return "The API rate limit was reached. Wait a moment and try again."
+	case errors.Is(err, api.ErrServer):
+		return "The Hetzner DNS API is having problems. Try again later."
+	}
+	return ""
+}
+
+// notFoundHint returns advice for a zone, record or primary server that was
+// not found by cmd
+func notFoundHint(cmd *cobra.Command) string {
+	group := ""
+	if cmd != nil {
+		// The command path is "hetznerdns <group> <command>"
+		if fields := strings.Fields(cmd.CommandPath()); len(fields) > 1 {
+			group = fields[1]
+		}
+	}
+
+	switch group {
+	case "zone":
+		return "Check the zone ID or name. Run 'hetznerdns zone list' to see your zones."
+	case "record":
+		return "Check the record ID and the zone. Run 'hetznerdns record list --zone ZONE' to see the records of a zone."
+	case "primary-server":
+		return "Check the primary server ID and the zone. Run 'hetznerdns primary-server list --zone ZONE' to see the primary servers of a zone."
+	}
+	return "Check the IDs and names given. Run 'hetznerdns zone list' to see your zones."
+}
+
+// printError writes err to w, followed by a hint on how to resolve it unless
+// --quiet is set
+func printError(w io.Writer, err error) {
+	var cmdErr *commandError
+	if errors.As(err, &cmdErr) {
+		fmt.Fprintln(w, err)
+	} else {
+		fmt.Fprintf(w, "Error: %v\n", err)
+	}
+	if hint := errorHint(err, runningCommand); hint != "" && !quiet {
+		fmt.Fprintf(w, "Hint: %s\n", hint)
+	}
+}
@@ -2,11 +2,21 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/shotgundd/hetznerdns/pkg/api"
+	"github.com/shotgundd/hetznerdns/pkg/api/apitest"
 )
 
 // TestMain builds the CLI binary for testing
@@ -17,12 +27,12 @@ func TestMain(m *testing.M) {
 		os.Stderr.WriteString("Failed to build test binary: " + err.Error() + "\n")
 		os.Exit(1)
 	}
-	defer os.Remove("hetznerdns_test")
 
 	// Run the tests
 	exitCode := m.Run()
 
-	// Exit with the same code
+	// os.Exit skips deferred calls, so remove the binary before exiting
+	os.Remove("hetznerdns_test")
 	os.Exit(exitCode)
 }
 
@@ -38,6 +48,19 @@ func runCommand(args ...string) (string, string, error) {
 	return stdout.String(), stderr.String(), err
 }
 
+// runCommandWithInput runs the CLI command with the given standard input
+func runCommandWithInput(input string, args ...string) (string, string, error) {
+	cmd := exec.Command("./hetznerdns_test", args...)
+	cmd.Stdin = strings.NewReader(input)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	return stdout.String(), stderr.String(), err
+}
+
 func TestVersionCommand(t *testing.T) {
 	stdout, stderr, err := runCommand("version")
 	if err != nil {
@@ -94,73 +117,969 @@ func TestConfigCommand(t *testing.T) {
 	}
 }
 
-// Note: The following tests require a valid API token and will make actual API calls.
-// They are commented out by default and should be run manually when needed.
+func TestEndpointOverride(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("HETZNER_DNS_API_TOKEN", "test-token")
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/zones" {
+			t.Errorf("Expected path /zones, got %s", r.URL.Path)
+		}
+		if r.Header.Get("Auth-API-Token") != "test-token" {
+			t.Errorf("Expected Auth-API-Token header 'test-token', got '%s'", r.Header.Get("Auth-API-Token"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"zones":[{"id":"zone1","name":"example.com","ttl":3600,"records_count":2}]}`))
+	}))
+	defer server.Close()
 
-/*
-func TestZoneCommands(t *testing.T) {
-	// Skip if no API token is provided
-	apiToken := os.Getenv("HETZNER_DNS_API_TOKEN")
-	if apiToken == "" {
-		t.Skip("Skipping test because HETZNER_DNS_API_TOKEN is not set")
+	// Endpoint given as a flag
+	stdout, stderr, err := runCommand("zone", "list", "--endpoint", server.URL)
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "example.com") {
+		t.Errorf("Expected zone list output, got: %s", stdout)
 	}
 
-	// Set up the API token for testing
-	_, stderr, err := runCommand("config", "set", "api-token", apiToken)
+	// Endpoint given through the environment
+	t.Setenv("HETZNER_DNS_ENDPOINT", server.URL)
+
+	stdout, stderr, err = runCommand("zone", "list")
 	if err != nil {
-		t.Fatalf("Failed to set API token: %v\nStderr: %s", err, stderr)
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "zone1") {
+		t.Errorf("Expected zone list output, got: %s", stdout)
 	}
+}
 
-	// Test listing zones
-	stdout, stderr, err := runCommand("zone", "list")
+// setupAPIServer starts a test server with the given handler and points the
+// CLI at it through the environment, using a temporary home directory
+func setupAPIServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("HETZNER_DNS_API_TOKEN", "test-token")
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	t.Setenv("HETZNER_DNS_ENDPOINT", server.URL)
+
+	return server
+}
+
+func TestZoneListPagination(t *testing.T) {
+	setupAPIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Write([]byte(`{"zones":[{"id":"zone1","name":"first.example"}],"meta":{"pagination":{"page":1,"per_page":1,"last_page":2,"total_entries":2}}}`))
+		case "2":
+			w.Write([]byte(`{"zones":[{"id":"zone2","name":"second.example"}],"meta":{"pagination":{"page":2,"per_page":1,"last_page":2,"total_entries":2}}}`))
+		default:
+			t.Errorf("Unexpected page %q", r.URL.Query().Get("page"))
+		}
+	})
+
+	// All pages are fetched by default
+	stdout, stderr, err := runCommand("zone", "list", "--per-page", "1")
 	if err != nil {
 		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
 	}
+	if !strings.Contains(stdout, "first.example") || !strings.Contains(stdout, "second.example") {
+		t.Errorf("Expected zones from both pages, got: %s", stdout)
+	}
 
-	if !strings.Contains(stdout, "ID") || !strings.Contains(stdout, "Name") {
-		t.Errorf("Expected zone list output, got: %s", stdout)
+	// A single page can be requested explicitly
+	stdout, stderr, err = runCommand("zone", "list", "--page", "2", "--per-page", "1")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	if strings.Contains(stdout, "first.example") || !strings.Contains(stdout, "Page 2 of 2") {
+		t.Errorf("Expected only the second page, got: %s", stdout)
+	}
+}
+
+func TestInterruptAbortsRequest(t *testing.T) {
+	received := make(chan struct{}, 1)
+	release := make(chan struct{})
+	defer close(release)
+
+	setupAPIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	})
+
+	cmd := exec.Command("./hetznerdns_test", "zone", "list")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Failed to start command: %v", err)
+	}
+
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		cmd.Process.Kill()
+		t.Fatal("Timed out waiting for the API request")
+	}
+
+	if err := cmd.Process.Signal(os.Interrupt); err != nil {
+		t.Fatalf("Failed to send interrupt: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		cmd.Process.Kill()
+		t.Fatal("Command did not exit after interrupt")
 	}
 }
 
-func TestRecordCommands(t *testing.T) {
-	// Skip if no API token is provided
-	apiToken := os.Getenv("HETZNER_DNS_API_TOKEN")
-	if apiToken == "" {
-		t.Skip("Skipping test because HETZNER_DNS_API_TOKEN is not set")
+func TestAPIErrorExitCodes(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		exitCode   int
+		hint       string
+	}{
+		{http.StatusUnauthorized, 3, "config set"},
+		{http.StatusNotFound, 4, "record list"},
+		{http.StatusUnprocessableEntity, 5, "record name, type and value"},
+		{http.StatusTooManyRequests, 6, "rate limit"},
+		{http.StatusInternalServerError, 1, "try again later"},
 	}
 
-	// Set up the API token for testing
-	_, stderr, err := runCommand("config", "set", "api-token", apiToken)
+	for _, tt := range tests {
+		setupAPIServer(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.statusCode)
+			w.Write([]byte(`{"error":{"message":"request failed","code":` + strconv.Itoa(tt.statusCode) + `}}`))
+		})
+
+		stdout, stderr, err := runCommand("record", "delete", "--id", "record1", "--retries", "0")
+		exitErr, ok := err.(*exec.ExitError)
+		if !ok {
+			t.Fatalf("Status %d: expected command to fail, got %v", tt.statusCode, err)
+		}
+		if exitErr.ExitCode() != tt.exitCode {
+			t.Errorf("Status %d: expected exit code %d, got %d", tt.statusCode, tt.exitCode, exitErr.ExitCode())
+		}
+		if !strings.Contains(strings.ToLower(stderr), strings.ToLower(tt.hint)) {
+			t.Errorf("Status %d: expected hint containing %q on stderr, got: %s", tt.statusCode, tt.hint, stderr)
+		}
+		if stdout != "" {
+			t.Errorf("Status %d: expected no output on stdout, got: %s", tt.statusCode, stdout)
+		}
+	}
+}
+
+func TestRetryFlags(t *testing.T) {
+	var calls atomic.Int32
+	setupAPIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"zones":[{"id":"zone1","name":"example.com"}]}`))
+	})
+
+	stdout, stderr, err := runCommand("zone", "list", "--retries", "2")
 	if err != nil {
-		t.Fatalf("Failed to set API token: %v\nStderr: %s", err, stderr)
+		t.Fatalf("Command failed: %v\nStdout: %s\nStderr: %s", err, stdout, stderr)
 	}
+	if !strings.Contains(stdout, "example.com") || calls.Load() != 3 {
+		t.Errorf("Expected success after 3 calls, got %d calls and output: %s", calls.Load(), stdout)
+	}
+
+	// Without retries the rate limit error is reported
+	calls.Store(0)
+	_, _, err = runCommand("zone", "list", "--retries", "0")
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 6 {
+		t.Errorf("Expected exit code 6, got %v", err)
+	}
+}
+
+func TestZoneLifecycleCommands(t *testing.T) {
+	var deleted atomic.Bool
+	setupAPIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/zones":
+			w.Write([]byte(`{"zones":[{"id":"zone1","name":"example.com","ttl":3600,"records_count":2,"status":"pending",
+				"ns":["hydrogen.ns.hetzner.com"],"created":"2018-08-23 13:21:39.489 +0000 UTC"}]}`))
+		case r.Method == "GET" && r.URL.Path == "/zones/zone1":
+			w.Write([]byte(`{"zone":{"id":"zone1","name":"example.com","ttl":3600,"records_count":2,"status":"pending",
+				"ns":["hydrogen.ns.hetzner.com"],"created":"2018-08-23 13:21:39.489 +0000 UTC"}}`))
+		case r.Method == "POST" && r.URL.Path == "/zones":
+			w.Write([]byte(`{"zone":{"id":"zone2","name":"new.example","ttl":86400}}`))
+		case r.Method == "PUT" && r.URL.Path == "/zones/zone1":
+			w.Write([]byte(`{"zone":{"id":"zone1","name":"example.com","ttl":600}}`))
+		case r.Method == "DELETE" && r.URL.Path == "/zones/zone1":
+			deleted.Store(true)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	stdout, stderr, err := runCommand("zone", "create", "--name", "new.example")
+	if err != nil || !strings.Contains(stdout, "zone2") {
+		t.Errorf("zone create failed: %v\nStdout: %s\nStderr: %s", err, stdout, stderr)
+	}
+
+	stdout, stderr, err = runCommand("zone", "get", "--zone", "example.com")
+	if err != nil || !strings.Contains(stdout, "Records:") || !strings.Contains(stdout, "example.com") {
+		t.Errorf("zone get failed: %v\nStdout: %s\nStderr: %s", err, stdout, stderr)
+	}
+
+	if !strings.Contains(stdout, "pending") || !strings.Contains(stdout, "hydrogen.ns.hetzner.com") {
+		t.Errorf("Expected zone get to show status and name servers, got: %s", stdout)
+	}
+
+	stdout, stderr, err = runCommand("zone", "list", "--wide")
+	if err != nil || !strings.Contains(stdout, "STATUS") || !strings.Contains(stdout, "2018-08-23 13:21:39") {
+		t.Errorf("zone list --wide failed: %v\nStdout: %s\nStderr: %s", err, stdout, stderr)
+	}
+
+	stdout, stderr, err = runCommand("zone", "update", "--zone", "example.com", "--ttl", "600")
+	if err != nil || !strings.Contains(stdout, "updated successfully") {
+		t.Errorf("zone update failed: %v\nStdout: %s\nStderr: %s", err, stdout, stderr)
+	}
+
+	// A wrong confirmation aborts the deletion
+	_, _, err = runCommandWithInput("wrong.example\n", "zone", "delete", "--zone", "zone1")
+	if err == nil || deleted.Load() {
+		t.Errorf("Expected zone delete to be aborted, got err=%v deleted=%v", err, deleted.Load())
+	}
+
+	stdout, stderr, err = runCommandWithInput("example.com\n", "zone", "delete", "--zone", "zone1")
+	if err != nil || !deleted.Load() {
+		t.Errorf("zone delete failed: %v\nStdout: %s\nStderr: %s", err, stdout, stderr)
+	}
+}
+
+func TestZoneExportCommand(t *testing.T) {
+	zoneFile := "$ORIGIN example.com.\nwww IN A 192.0.2.1\n"
+	setupAPIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/zones":
+			w.Write([]byte(`{"zones":[{"id":"zone1","name":"example.com"}]}`))
+		case "/zones/zone1/export":
+			w.Write([]byte(zoneFile))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	stdout, stderr, err := runCommand("zone", "export", "--zone", "zone1")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	if !strings.HasSuffix(stdout, zoneFile) {
+		t.Errorf("Expected zone file on stdout, got: %s", stdout)
+	}
+
+	output := filepath.Join(t.TempDir(), "db.example.com")
+	if _, stderr, err := runCommand("zone", "export", "--zone", "example.com", "--file", output); err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	written, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("Zone file was not written: %v", err)
+	}
+	if string(written) != zoneFile {
+		t.Errorf("Expected %q in zone file, got %q", zoneFile, written)
+	}
+
+	// Zone files have no other output formats
+	_, _, err = runCommand("zone", "export", "--zone", "example.com", "-o", "json")
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != exitUsage {
+		t.Errorf("Expected exit code %d for -o json, got %v", exitUsage, err)
+	}
+}
+
+func TestZoneImportCommand(t *testing.T) {
+	var imported atomic.Bool
+	setupAPIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/zones":
+			w.Write([]byte(`{"zones":[{"id":"zone1","name":"example.com"}]}`))
+		case r.URL.Path == "/zones/zone1":
+			w.Write([]byte(`{"zone":{"id":"zone1","name":"example.com","records_count":5}}`))
+		case r.URL.Path == "/zones/file/validate" && strings.Contains(string(body), "broken"):
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"error":{"message":"zone file could not be parsed","code":422}}`))
+		case r.URL.Path == "/zones/file/validate":
+			w.Write([]byte(`{"parsed_records":1,"valid_records":[{"name":"www","type":"A","value":"192.0.2.1"}]}`))
+		case r.URL.Path == "/zones/zone1/import":
+			imported.Store(true)
+			w.Write([]byte(`{"zone":{"id":"zone1","name":"example.com","records_count":1}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	dir := t.TempDir()
+	validFile := filepath.Join(dir, "db.example.com")
+	os.WriteFile(validFile, []byte("www IN A 192.0.2.1\n"), 0644)
+	brokenFile := filepath.Join(dir, "db.broken")
+	os.WriteFile(brokenFile, []byte("broken\n"), 0644)
+
+	// Validation only shows the parsed records and does not import
+	stdout, stderr, err := runCommand("zone", "import", "--zone", "example.com", "--file", validFile, "--validate-only")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "192.0.2.1") || !strings.Contains(stdout, "Zone file is valid") || imported.Load() {
+		t.Errorf("Unexpected validation output (imported=%v): %s", imported.Load(), stdout)
+	}
+
+	// Parse errors make validation fail with the validation exit code
+	_, _, err = runCommand("zone", "import", "--zone", "example.com", "--file", brokenFile, "--validate-only")
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 5 {
+		t.Errorf("Expected exit code 5 for a broken zone file, got %v", err)
+	}
+
+	// Declining the confirmation leaves the zone untouched
+	_, stderr, _ = runCommandWithInput("n\n", "zone", "import", "--zone", "example.com", "--file", validFile)
+	if imported.Load() || !strings.Contains(stderr, "replace the 5 existing records") {
+		t.Errorf("Expected import to be cancelled (imported=%v): %s", imported.Load(), stderr)
+	}
+
+	// Other formats print the valid records and show the parsed records on stderr
+	stdout, stderr, err = runCommand("zone", "import", "--zone", "example.com", "--file", validFile, "--validate-only", "-o", "json")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	var records []api.Record
+	if err := json.Unmarshal([]byte(stdout), &records); err != nil || len(records) != 1 || records[0].Value != "192.0.2.1" {
+		t.Errorf("Expected the valid records as JSON, got %v: %s", err, stdout)
+	}
+	if !strings.Contains(stderr, "Parsed 1 records") {
+		t.Errorf("Expected the parsed records on stderr, got: %s", stderr)
+	}
+
+	stdout, stderr, err = runCommand("zone", "import", "--zone", "example.com", "--file", validFile, "--yes", "-o", "id")
+	if err != nil || !imported.Load() {
+		t.Errorf("zone import failed: %v\nStdout: %s\nStderr: %s", err, stdout, stderr)
+	}
+	if stdout != "zone1\n" {
+		t.Errorf("Expected the zone ID, got: %s", stdout)
+	}
+}
+
+func TestRecordCreateFromFile(t *testing.T) {
+	var bulkBody atomic.Value
+	setupAPIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/zones":
+			w.Write([]byte(`{"zones":[{"id":"zone1","name":"example.com"}]}`))
+		case r.Method == "POST" && r.URL.Path == "/records/bulk":
+			bulkBody.Store(string(body))
+			w.Write([]byte(`{
+				"records":[{"id":"record1","type":"A","name":"www","value":"192.0.2.1","zone_id":"zone1"}],
+				"valid_records":[{"type":"A","name":"www","value":"192.0.2.1","zone_id":"zone1"}],
+				"invalid_records":[{"type":"AAAA","name":"www","value":"nope","zone_id":"zone1"}]}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	dir := t.TempDir()
+	csvFile := filepath.Join(dir, "records.csv")
+	os.WriteFile(csvFile, []byte("name,type,value,ttl\nwww,A,192.0.2.1,3600\nwww,AAAA,nope,\nmail,MX,\n"), 0644)
+
+	stdout, stderr, err := runCommand("record", "create", "--zone", "example.com", "--from-file", csvFile)
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 5 {
+		t.Errorf("Expected exit code 5 for rejected records, got %v", err)
+	}
+	submitted, _ := bulkBody.Load().(string)
+	if !strings.Contains(submitted, `"ttl":3600`) || strings.Contains(submitted, "mail") {
+		t.Errorf("Unexpected bulk request body: %s", submitted)
+	}
+	if !strings.Contains(stdout, "Created 1 of 3 records") {
+		t.Errorf("Expected a summary in output, got: %s", stdout)
+	}
+	for _, want := range []string{"missing value", "www AAAA nope: rejected by the API", "records rejected"} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("Expected %q on stderr, got: %s", want, stderr)
+		}
+	}
+
+	// Records must name their zone when --zone is not given
+	yamlFile := filepath.Join(dir, "records.yaml")
+	os.WriteFile(yamlFile, []byte("records:\n  - name: www\n    type: A\n    value: 192.0.2.1\n"), 0644)
+	_, stderr, _ = runCommand("record", "create", "--from-file", yamlFile)
+	if !strings.Contains(stderr, "missing zone") {
+		t.Errorf("Expected missing zone to be reported, got: %s", stderr)
+	}
+}
+
+func TestRecordGetCommand(t *testing.T) {
+	setupAPIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/records/record1" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":{"message":"record not found","code":404}}`))
+			return
+		}
+		w.Write([]byte(`{"record":{"id":"record1","zone_id":"zone1","type":"A","name":"www","value":"192.0.2.1","ttl":3600,
+			"created":"2024-01-02 03:04:05.123 +0000 UTC","modified":"2024-02-03 04:05:06.456 +0000 UTC"}}`))
+	})
+
+	stdout, stderr, err := runCommand("record", "get", "--id", "record1")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	for _, want := range []string{"192.0.2.1", "zone1", "2024-02-03 04:05:06"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("Expected %q in output, got: %s", want, stdout)
+		}
+	}
+
+	stdout, stderr, err = runCommand("record", "get", "--id", "record1", "-o", "json")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, `"created": "2024-01-02T03:04:05.123Z"`) {
+		t.Errorf("Expected JSON output, got: %s", stdout)
+	}
+
+	// Scripts can check whether a record still exists by the exit code
+	_, _, err = runCommand("record", "get", "--id", "gone", "--retries", "0")
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 4 {
+		t.Errorf("Expected exit code 4 for a missing record, got %v", err)
+	}
+}
+
+func TestPrimaryServerCommands(t *testing.T) {
+	var removed atomic.Bool
+	setupAPIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.URL.Path == "/zones":
+			w.Write([]byte(`{"zones":[{"id":"zone1","name":"example.com","is_secondary_dns":true}]}`))
+		case r.Method == "GET" && r.URL.Path == "/primary_servers" && r.URL.Query().Get("zone_id") == "zone1":
+			w.Write([]byte(`{"primary_servers":[{"id":"ps1","address":"192.0.2.53","port":53,"zone_id":"zone1"}]}`))
+		case r.Method == "POST" && r.URL.Path == "/primary_servers":
+			if !strings.Contains(string(body), `"port":5353`) || !strings.Contains(string(body), `"zone_id":"zone1"`) {
+				t.Errorf("Unexpected request body: %s", body)
+			}
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"primary_server":{"id":"ps2","address":"192.0.2.54","port":5353,"zone_id":"zone1"}}`))
+		case r.Method == "DELETE" && r.URL.Path == "/primary_servers/ps1":
+			removed.Store(true)
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
 
-	// Get a zone ID for testing
 	stdout, stderr, err := runCommand("zone", "list")
+	if err != nil || !strings.Contains(stdout, "SECONDARY") || !strings.Contains(stdout, "true") {
+		t.Errorf("Expected zone list to show secondary zones: %v\nStdout: %s\nStderr: %s", err, stdout, stderr)
+	}
+
+	stdout, stderr, err = runCommand("primary-server", "list", "--zone", "example.com")
+	if err != nil || !strings.Contains(stdout, "192.0.2.53") {
+		t.Errorf("primary-server list failed: %v\nStdout: %s\nStderr: %s", err, stdout, stderr)
+	}
+
+	stdout, stderr, err = runCommand("primary-server", "add", "--zone", "example.com", "--address", "192.0.2.54", "--port", "5353")
+	if err != nil || !strings.Contains(stdout, "ps2") {
+		t.Errorf("primary-server add failed: %v\nStdout: %s\nStderr: %s", err, stdout, stderr)
+	}
+
+	stdout, stderr, err = runCommand("primary-server", "remove", "--id", "ps1")
+	if err != nil || !removed.Load() {
+		t.Errorf("primary-server remove failed: %v\nStdout: %s\nStderr: %s", err, stdout, stderr)
+	}
+}
+
+func TestRecordListModifiedSince(t *testing.T) {
+	recent := time.Now().Add(-time.Hour).UTC().Format("2006-01-02 15:04:05.000 -0700 MST")
+	setupAPIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/zones":
+			w.Write([]byte(`{"zones":[{"id":"zone1","name":"example.com"}]}`))
+		case "/records":
+			w.Write([]byte(`{"records":[
+				{"id":"old","type":"A","name":"old","value":"192.0.2.1","zone_id":"zone1",
+					"created":"2020-01-01 00:00:00 +0000 UTC","modified":"2020-01-02 00:00:00 +0000 UTC"},
+				{"id":"new","type":"A","name":"new","value":"192.0.2.2","zone_id":"zone1",
+					"created":"` + recent + `","modified":"` + recent + `"}]}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	})
+
+	stdout, stderr, err := runCommand("record", "list", "--zone", "example.com", "--wide")
+	if err != nil || !strings.Contains(stdout, "MODIFIED") || !strings.Contains(stdout, "2020-01-02 00:00:00") {
+		t.Errorf("record list --wide failed: %v\nStdout: %s\nStderr: %s", err, stdout, stderr)
+	}
+
+	stdout, stderr, err = runCommand("record", "list", "--zone", "example.com", "--modified-since", "24h")
+	if err != nil || strings.Contains(stdout, "192.0.2.1") || !strings.Contains(stdout, "192.0.2.2") {
+		t.Errorf("Expected only the recently modified record: %v\nStdout: %s\nStderr: %s", err, stdout, stderr)
+	}
+
+	_, _, err = runCommand("record", "list", "--zone", "example.com", "--modified-since", "yesterday")
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 2 {
+		t.Errorf("Expected exit code 2 for an invalid --modified-since, got %v", err)
+	}
+}
+
+func TestDebugTracing(t *testing.T) {
+	setupAPIServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"zones":[{"id":"zone1","name":"example.com"}]}`))
+	})
+
+	harPath := filepath.Join(t.TempDir(), "session.har")
+	stdout, stderr, err := runCommand("zone", "list", "--debug", "--har", harPath)
 	if err != nil {
 		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
 	}
+	if strings.Contains(stdout, "-->") || !strings.Contains(stderr, "--> GET ") || !strings.Contains(stderr, "<-- 200 OK") {
+		t.Errorf("Expected the debug log on stderr only\nStdout: %s\nStderr: %s", stdout, stderr)
+	}
+	if strings.Contains(stderr, "test-token") || !strings.Contains(stderr, "[REDACTED]") {
+		t.Errorf("Expected the API token to be redacted, got: %s", stderr)
+	}
 
-	lines := strings.Split(stdout, "\n")
-	if len(lines) < 2 {
-		t.Skip("Skipping test because no zones are available")
+	har, err := os.ReadFile(harPath)
+	if err != nil {
+		t.Fatalf("Expected a HAR file, got %v", err)
+	}
+	if !strings.Contains(string(har), `"entries"`) || !strings.Contains(string(har), "example.com") || strings.Contains(string(har), "test-token") {
+		t.Errorf("Unexpected HAR file: %s", har)
 	}
 
-	// Extract zone ID from the first zone in the list
-	fields := strings.Fields(lines[1])
-	if len(fields) < 1 {
-		t.Skip("Skipping test because zone ID could not be extracted")
+	// HETZNER_DNS_DEBUG enables the debug log as well
+	t.Setenv("HETZNER_DNS_DEBUG", "1")
+	_, stderr, _ = runCommand("zone", "list")
+	if !strings.Contains(stderr, "--> GET ") {
+		t.Errorf("Expected HETZNER_DNS_DEBUG to enable the debug log, got: %s", stderr)
 	}
-	zoneID := fields[0]
+}
+
+// setupFakeAPI starts an in-memory fake of the DNS API and points the CLI at
+// it through the environment, using a temporary home directory
+func setupFakeAPI(t *testing.T) *apitest.Server {
+	t.Helper()
+
+	server := apitest.NewServer()
+	t.Cleanup(server.Close)
 
-	// Test listing records for the zone
-	stdout, stderr, err = runCommand("record", "list", "--zone-id", zoneID)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("HETZNER_DNS_API_TOKEN", server.Token)
+	t.Setenv("HETZNER_DNS_ENDPOINT", server.URL)
+
+	return server
+}
+
+func TestRecordLifecycleAgainstFakeAPI(t *testing.T) {
+	server := setupFakeAPI(t)
+
+	_, stderr, err := runCommand("zone", "create", "--name", "example.com")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+
+	stdout, stderr, err := runCommand("record", "create", "--zone", "example.com", "--type", "A", "--name", "www", "--value", "192.0.2.1")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStdout: %s\nStderr: %s", err, stdout, stderr)
+	}
+	records := server.Records("")
+	if len(records) != 1 || records[0].Value != "192.0.2.1" {
+		t.Fatalf("Expected the record to be created, got %+v", records)
+	}
+	recordID := records[0].ID
+
+	stdout, stderr, err = runCommand("record", "list", "--zone", "example.com")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, recordID) || !strings.Contains(stdout, "192.0.2.1") {
+		t.Errorf("Expected the record in the list, got: %s", stdout)
+	}
+
+	_, stderr, err = runCommand("record", "update", "--id", recordID, "--zone", "example.com", "--value", "192.0.2.2")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	if records := server.Records(""); records[0].Value != "192.0.2.2" || records[0].Name != "www" {
+		t.Errorf("Expected only the value to change, got %+v", records[0])
+	}
+
+	// The fake rejects invalid values like the API does
+	_, _, err = runCommand("record", "create", "--zone", "example.com", "--type", "A", "--name", "bad", "--value", "not-an-ip", "--skip-validation")
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != exitValidation {
+		t.Errorf("Expected exit code %d for an invalid record, got %v", exitValidation, err)
+	}
+
+	_, stderr, err = runCommand("record", "delete", "--id", recordID)
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	if records := server.Records(""); len(records) != 0 {
+		t.Errorf("Expected the record to be deleted, got %+v", records)
+	}
+}
+
+func TestZoneCacheCommands(t *testing.T) {
+	server := setupFakeAPI(t)
+	zone := server.AddZone(api.Zone{Name: "example.com"})
+
+	// zoneLookups counts the requests that looked up the zone by name
+	zoneLookups := func() int {
+		count := 0
+		for _, request := range server.Requests() {
+			if strings.HasPrefix(request, "GET /zones?name=") {
+				count++
+			}
+		}
+		return count
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, stderr, err := runCommand("record", "list", "--zone", "example.com"); err != nil {
+			t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+		}
+	}
+	if zoneLookups() != 1 {
+		t.Errorf("Expected the zone ID to be looked up once, got requests %v", server.Requests())
+	}
+
+	if _, stderr, err := runCommand("record", "list", "--zone", "example.com", "--no-cache"); err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	if zoneLookups() != 2 {
+		t.Errorf("Expected --no-cache to look up the zone ID, got requests %v", server.Requests())
+	}
+
+	// Deleting the zone removes it from the cache
+	if _, stderr, err := runCommand("zone", "delete", "--zone", zone.ID, "--confirm", "example.com"); err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	_, _, err := runCommand("record", "list", "--zone", "example.com")
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != exitNotFound {
+		t.Errorf("Expected exit code %d for the deleted zone, got %v", exitNotFound, err)
+	}
+
+	// A recreated zone gets a new ID, which zone create caches
+	if _, stderr, err := runCommand("zone", "create", "--name", "example.com"); err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	lookups := zoneLookups()
+	if _, stderr, err := runCommand("record", "list", "--zone", "example.com"); err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	if zoneLookups() != lookups {
+		t.Errorf("Expected the created zone to be cached, got requests %v", server.Requests())
+	}
+
+	stdout, stderr, err := runCommand("cache", "clear")
+	if err != nil || !strings.Contains(stdout, "cleared") {
+		t.Fatalf("Command failed: %v\nStdout: %s\nStderr: %s", err, stdout, stderr)
+	}
+	if _, stderr, err := runCommand("record", "list", "--zone", "example.com"); err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	if zoneLookups() != lookups+1 {
+		t.Errorf("Expected the zone ID to be looked up after cache clear, got requests %v", server.Requests())
+	}
+}
+
+func TestRecordValueValidation(t *testing.T) {
+	server := setupFakeAPI(t)
+	server.AddZone(api.Zone{Name: "example.com"})
+
+	_, stderr, err := runCommand("record", "create", "--zone", "example.com", "--type", "MX", "--name", "@", "--value", "mail.example.com")
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != exitValidation {
+		t.Fatalf("Expected exit code %d for an MX record without priority, got %v", exitValidation, err)
+	}
+	if !strings.Contains(stderr, "expected 2 fields (priority mail-server)") || !strings.Contains(stderr, "--skip-validation") {
+		t.Errorf("Expected the reason and a hint, got: %s", stderr)
+	}
+	if requests := server.Requests(); len(requests) != 0 {
+		t.Errorf("Expected the record to be rejected before calling the API, got requests %v", requests)
+	}
+
+	// --skip-validation leaves the decision to the API
+	_, stderr, err = runCommand("record", "create", "--zone", "example.com", "--type", "MX", "--name", "@", "--value", "mail.example.com", "--skip-validation")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	records := server.Records("")
+	if len(records) != 1 {
+		t.Fatalf("Expected the record to be created, got %+v", records)
+	}
+
+	// Changing only the TTL does not check the value the record already has
+	_, stderr, err = runCommand("record", "update", "--id", records[0].ID, "--zone", "example.com", "--ttl", "600")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	if records := server.Records(""); len(records) != 1 || records[0].TTL != 600 {
+		t.Errorf("Expected the TTL to be updated, got %+v", records)
+	}
+
+	// A new value is checked
+	_, _, err = runCommand("record", "update", "--id", records[0].ID, "--zone", "example.com", "--value", "mail.example.org")
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != exitValidation {
+		t.Errorf("Expected exit code %d for an invalid new value, got %v", exitValidation, err)
+	}
+}
+
+func TestOutputFormats(t *testing.T) {
+	server := setupFakeAPI(t)
+	zone := server.AddZone(api.Zone{Name: "example.com"})
+
+	stdout, stderr, err := runCommand("record", "create", "--zone", "example.com", "--type", "A", "--name", "www", "--value", "192.0.2.1", "-o", "id")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	recordID := strings.TrimSpace(stdout)
+	if records := server.Records(zone.ID); len(records) != 1 || records[0].ID != recordID {
+		t.Fatalf("Expected -o id to print only the new record ID, got %q", stdout)
+	}
+
+	stdout, stderr, err = runCommand("record", "get", "--id", recordID, "-o", "json")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	var record api.Record
+	if err := json.Unmarshal([]byte(stdout), &record); err != nil || record.Value != "192.0.2.1" {
+		t.Errorf("Expected the record as JSON, got %q (%v)", stdout, err)
+	}
+
+	stdout, stderr, err = runCommand("zone", "list", "-o", "jsonpath={.name} {.records_count}")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	if stdout != "example.com 1\n" {
+		t.Errorf("Expected the jsonpath result, got %q", stdout)
+	}
+
+	stdout, stderr, err = runCommand("record", "delete", "--id", recordID, "-o", "json")
 	if err != nil {
 		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
 	}
+	if !strings.Contains(stdout, `"deleted": true`) || !strings.Contains(stdout, recordID) {
+		t.Errorf("Expected a JSON delete result, got %q", stdout)
+	}
+
+	_, _, err = runCommand("zone", "list", "-o", "xml")
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != exitUsage {
+		t.Errorf("Expected exit code %d for an unknown output format, got %v", exitUsage, err)
+	}
+}
+
+func TestDiagnosticsOnStderr(t *testing.T) {
+	server := setupFakeAPI(t)
+	server.AddZone(api.Zone{Name: "example.com"})
+
+	// Failures write nothing to stdout, so pipelines do not parse error text
+	stdout, stderr, err := runCommand("record", "list", "--zone", "example.org", "-o", "json")
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != exitNotFound {
+		t.Fatalf("Expected exit code %d for an unknown zone, got %v", exitNotFound, err)
+	}
+	if stdout != "" || !strings.Contains(stderr, "Error resolving zone") || !strings.Contains(stderr, "Hint:") {
+		t.Errorf("Expected the error on stderr only, got stdout %q, stderr %q", stdout, stderr)
+	}
+
+	// --quiet leaves out notices and hints, but not the error
+	_, stderr, _ = runCommand("record", "list", "--zone", "example.org", "--quiet")
+	if !strings.Contains(stderr, "Error resolving zone") || strings.Contains(stderr, "Hint:") || strings.Contains(stderr, "Notice:") {
+		t.Errorf("Expected only the error with --quiet, got: %s", stderr)
+	}
+
+	stdout, stderr, err = runCommand("zone", "list", "-o", "name", "-q")
+	if err != nil || stdout != "example.com\n" || stderr != "" {
+		t.Errorf("Expected only the zone name with --quiet, got %v, stdout %q, stderr %q", err, stdout, stderr)
+	}
+
+	// Usage errors exit with exitUsage before any request is sent
+	requests := len(server.Requests())
+	_, stderr, err = runCommand("record", "create", "--name", "www", "--type", "A", "--value", "192.0.2.1")
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != exitUsage {
+		t.Errorf("Expected exit code %d without --zone, got %v", exitUsage, err)
+	}
+	if !strings.Contains(stderr, `"zone" not set`) || len(server.Requests()) != requests {
+		t.Errorf("Expected a usage error without requests, got: %s", stderr)
+	}
+
+	// A missing API token is an authentication error
+	t.Setenv("HETZNER_DNS_API_TOKEN", "")
+	_, stderr, err = runCommand("zone", "list")
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != exitAuth {
+		t.Errorf("Expected exit code %d without an API token, got %v", exitAuth, err)
+	}
+	if !strings.Contains(stderr, "API token not set") || !strings.Contains(stderr, "config set") {
+		t.Errorf("Expected the missing token to be reported, got: %s", stderr)
+	}
+}
+
+func TestRecordListFilters(t *testing.T) {
+	server := setupFakeAPI(t)
+	zone := server.AddZone(api.Zone{Name: "example.com"})
+	for _, record := range []api.Record{
+		{Name: "www", Type: "A", Value: "10.0.0.5", TTL: 300},
+		{Name: "api", Type: "A", Value: "203.0.113.10"},
+		{Name: "_dmarc", Type: "TXT", Value: `"v=DMARC1; p=reject"`, TTL: 3600},
+		{Name: "_dmarc.shop", Type: "TXT", Value: `"v=DMARC1; p=none"`},
+		{Name: "www", Type: "AAAA", Value: "2001:db8::5", TTL: 3600},
+	} {
+		record.ZoneID = zone.ID
+		server.AddRecord(record)
+	}
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"--type", "TXT", "--name", "_dmarc*"}, "_dmarc 'v=DMARC1; p=reject' _dmarc.shop 'v=DMARC1; p=none'"},
+		{[]string{"--value", "10.0.0.0/8"}, "www 10.0.0.5"},
+		{[]string{"--name", "/^w/", "--sort-by", "-ttl"}, "www 2001:db8::5 www 10.0.0.5"},
+		{[]string{"--ttl", "default", "-t", "a"}, "api 203.0.113.10"},
+		{[]string{"--value", "/p=none/"}, "_dmarc.shop 'v=DMARC1; p=none'"},
+	}
+
+	for _, tt := range tests {
+		args := append([]string{"record", "list", "--zone", "example.com", "-o", `jsonpath={.name} {.value}`}, tt.args...)
+		stdout, stderr, err := runCommand(args...)
+		if err != nil {
+			t.Fatalf("%v: command failed: %v\nStderr: %s", tt.args, err, stderr)
+		}
+		got := strings.ReplaceAll(strings.Join(strings.Fields(stdout), " "), `"`, "'")
+		if got != tt.want {
+			t.Errorf("%v: expected %q, got %q", tt.args, tt.want, got)
+		}
+	}
+
+	// Invalid patterns are usage errors
+	for _, args := range [][]string{{"--name", "/(/"}, {"--ttl", "soon"}, {"--sort-by", "priority"}} {
+		_, _, err := runCommand(append([]string{"record", "list", "--zone", "example.com"}, args...)...)
+		if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != exitUsage {
+			t.Errorf("%v: expected exit code %d, got %v", args, exitUsage, err)
+		}
+	}
+}
+
+func TestRecordSearch(t *testing.T) {
+	server := setupFakeAPI(t)
+	for _, name := range []string{"example.com", "example.org", "shop.example.com"} {
+		zone := server.AddZone(api.Zone{Name: name})
+		server.AddRecord(api.Record{ZoneID: zone.ID, Name: "www", Type: "A", Value: "203.0.113.10"})
+		server.AddRecord(api.Record{ZoneID: zone.ID, Name: "old", Type: "A", Value: "203.0.113.100"})
+		server.AddRecord(api.Record{ZoneID: zone.ID, Name: "@", Type: "MX", Value: "10 mail.example.net"})
+	}
+
+	stdout, stderr, err := runCommand("record", "search", "--value", "203.0.113.10", "-o", "name")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	if stdout != "www.example.com\nwww.example.org\nwww.shop.example.com\n" {
+		t.Errorf("Unexpected search results: %s", stdout)
+	}
+
+	stdout, stderr, err = runCommand("record", "search", "--value", "mail.example.net", "--type", "MX", "--zones", "*.example.com,example.org")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	if !strings.Contains(stdout, "shop.example.com") || !strings.Contains(stdout, "example.org") ||
+		strings.Contains(stdout, "\nexample.com") || strings.Count(stdout, "10 mail.example.net") != 2 {
+		t.Errorf("Unexpected search results: %s", stdout)
+	}
+
+	// Zones that fail are reported after the results of the other zones
+	server.FailNext(http.MethodGet, "/records", http.StatusNotFound, 1)
+	stdout, stderr, err = runCommand("record", "search", "--value", "203.0.113.0/24", "-o", "id", "--concurrency", "1", "--retries", "0")
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != exitNotFound {
+		t.Errorf("Expected exit code %d for a failed zone, got %v", exitNotFound, err)
+	}
+	if strings.Count(stdout, "\n") != 4 || !strings.Contains(stderr, "1 of 3 zones could not be searched") {
+		t.Errorf("Expected the matches of two zones and an error, got stdout %q, stderr %q", stdout, stderr)
+	}
+}
+
+func TestRecordReplace(t *testing.T) {
+	server := setupFakeAPI(t)
+	var records []api.Record
+	for _, name := range []string{"example.com", "example.org"} {
+		zone := server.AddZone(api.Zone{Name: name})
+		records = append(records,
+			server.AddRecord(api.Record{ZoneID: zone.ID, Name: "www", Type: "A", Value: "203.0.113.10"}),
+			server.AddRecord(api.Record{ZoneID: zone.ID, Name: "old", Type: "A", Value: "203.0.113.100"}),
+			server.AddRecord(api.Record{ZoneID: zone.ID, Name: "@", Type: "TXT", Value: `"v=spf1 ip4:203.0.113.10 -all"`}),
+		)
+	}
+	values := func() map[string]string {
+		values := make(map[string]string)
+		for _, zone := range server.Zones() {
+			for _, record := range server.Records(zone.ID) {
+				values[record.ID] = record.Value
+			}
+		}
+		return values
+	}
+	before := values()
+
+	// The plan is shown as a diff, declining or --dry-run changes nothing
+	stdout, stderr, err := runCommand("record", "replace", "--from", "203.0.113.10", "--to", "198.51.100.7", "--dry-run")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	for _, want := range []string{"www.example.org A", "- 203.0.113.10\n+ 198.51.100.7\n", "4 records in 2 zones will be changed"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("Expected %q in the plan, got: %s", want, stdout)
+		}
+	}
+	_, stderr, err = runCommandWithInput("n\n", "record", "replace", "--from", "203.0.113.10", "--to", "198.51.100.7")
+	if err != nil || !strings.Contains(stderr, "Update 4 records?") {
+		t.Errorf("Expected a confirmation prompt, got %v: %s", err, stderr)
+	}
+	if after := values(); len(after) != len(before) || after[records[0].ID] != "203.0.113.10" {
+		t.Fatalf("Expected no changes without confirmation, got %v", after)
+	}
+
+	// Records that fail are reported and updated by running the command again
+	server.FailNext(http.MethodPut, "/records/"+records[3].ID, http.StatusInternalServerError, 1)
+	stdout, stderr, err = runCommand("record", "replace", "--from", "203.0.113.10", "--to", "198.51.100.7", "--type", "A", "--yes", "--retries", "0")
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != exitGeneric {
+		t.Errorf("Expected exit code %d for a failed update, got %v", exitGeneric, err)
+	}
+	if !strings.Contains(stdout, "Updated 1 of 2 records") || !strings.Contains(stderr, "Failed: www.example.org A") ||
+		!strings.Contains(stderr, "[1/2] Updated www.example.com A") {
+		t.Errorf("Unexpected output\nStdout: %s\nStderr: %s", stdout, stderr)
+	}
+
+	stdout, stderr, err = runCommand("record", "replace", "--from", "203.0.113.10", "--to", "198.51.100.7", "--yes", "-o", "id")
+	if err != nil {
+		t.Fatalf("Command failed: %v\nStderr: %s", err, stderr)
+	}
+	if stdout != records[2].ID+"\n"+records[5].ID+"\n"+records[3].ID+"\n" {
+		t.Errorf("Expected the IDs of the remaining records, got: %s", stdout)
+	}
+
+	after := values()
+	for i, record := range records {
+		want := []string{"198.51.100.7", "203.0.113.100", `"v=spf1 ip4:198.51.100.7 -all"`}[i%3]
+		if after[record.ID] != want {
+			t.Errorf("Record %s %s: expected %q, got %q", record.Name, record.ZoneID, want, after[record.ID])
+		}
+	}
 
-	if !strings.Contains(stdout, "ID") || !strings.Contains(stdout, "Type") {
-		t.Errorf("Expected record list output, got: %s", stdout)
+	// Invalid new values are rejected before anything is changed
+	_, stderr, err = runCommand("record", "replace", "--from", "198.51.100.7", "--to", "host.example.com", "--type", "A", "--yes")
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != exitValidation || !strings.Contains(stderr, "not an IPv4 address") {
+		t.Errorf("Expected a validation error, got %v: %s", err, stderr)
 	}
 }
-*/
@@ -38,14 +38,14 @@ var llmHelpCmd = &cobra.Command{
 	Short:  "Print all commands and options in a machine-readable format for language models",
 	Long:   "Print detailed information about all commands, flags, and examples in a JSON format that's easy for language models to parse and understand.",
 	Hidden: false,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		options := getCommandOptions(rootCmd)
 		output, err := json.MarshalIndent(options, "", "  ")
 		if err != nil {
-			fmt.Printf("Error marshaling options: %v\n", err)
-			return
+			return withContext("Error marshaling options", err)
 		}
 		fmt.Println(string(output))
+		return nil
 	},
 }
 
@@ -109,16 +109,86 @@ func parseExamples(cmdName string) []Example {
 			},
 		}...)
 	case "zone":
-		examples = append(examples, Example{
-			Description: "List all DNS zones",
-			Command:     "hetznerdns zone list",
-		})
+		examples = append(examples, []Example{
+			{
+				Description: "List all DNS zones",
+				Command:     "hetznerdns zone list",
+			},
+			{
+				Description: "List zone names and record counts for scripts",
+				Command:     "hetznerdns zone list -o jsonpath='{.name} {.records_count}'",
+			},
+			{
+				Description: "Create a zone",
+				Command:     "hetznerdns zone create --name example.com --ttl 86400",
+			},
+			{
+				Description: "Show a zone",
+				Command:     "hetznerdns zone get --zone example.com",
+			},
+			{
+				Description: "Change the default TTL of a zone",
+				Command:     "hetznerdns zone update --zone example.com --ttl 3600",
+			},
+			{
+				Description: "Delete a zone without prompting",
+				Command:     "hetznerdns zone delete --zone example.com --confirm example.com",
+			},
+			{
+				Description: "Export a zone file",
+				Command:     "hetznerdns zone export --zone example.com --file db.example.com",
+			},
+			{
+				Description: "Validate a zone file without importing it",
+				Command:     "hetznerdns zone import --zone example.com --file db.example.com --validate-only",
+			},
+			{
+				Description: "Import a zone file without prompting",
+				Command:     "hetznerdns zone import --zone example.com --file db.example.com --yes",
+			},
+		}...)
 	case "record":
 		examples = append(examples, []Example{
 			{
 				Description: "List records for a zone",
 				Command:     "hetznerdns record list --zone example.com",
 			},
+			{
+				Description: "List records for a zone as JSON",
+				Command:     "hetznerdns record list --zone example.com -o json",
+			},
+			{
+				Description: "List records changed in the last 24 hours with their timestamps",
+				Command:     "hetznerdns record list --zone example.com --modified-since 24h --wide",
+			},
+			{
+				Description: "List the TXT records under _dmarc",
+				Command:     "hetznerdns record list --zone example.com --type TXT --name '_dmarc*'",
+			},
+			{
+				Description: "List the A records pointing into 10.0.0.0/8, sorted by name",
+				Command:     "hetznerdns record list --zone example.com --type A --value 10.0.0.0/8 --sort-by name",
+			},
+			{
+				Description: "Find every record in any zone that points at an IP address",
+				Command:     "hetznerdns record search --value 203.0.113.10",
+			},
+			{
+				Description: "Find the CNAME and MX records referencing a host in some zones",
+				Command:     "hetznerdns record search --value server1.example.net --type CNAME,MX --zones '*.example.com'",
+			},
+			{
+				Description: "Show which records would change when a server gets a new IP address",
+				Command:     "hetznerdns record replace --from 203.0.113.10 --to 198.51.100.7 --dry-run",
+			},
+			{
+				Description: "Rename a CNAME target in some zones without prompting",
+				Command:     "hetznerdns record replace --from old-host.example.net --to new-host.example.net --type CNAME --zones '*.example.com' --yes",
+			},
+			{
+				Description: "Show a record as JSON",
+				Command:     "hetznerdns record get --id RECORD_ID -o json",
+			},
 			{
 				Description: "Create an A record",
 				Command:     "hetznerdns record create --zone example.com --name www --type A --value 192.168.1.1 --ttl 3600",
@@ -131,6 +201,18 @@ func parseExamples(cmdName string) []Example {
 				Description: "Create an MX record",
 				Command:     "hetznerdns record create --zone example.com --name @ --type MX --value \"10 mail.example.com\"",
 			},
+			{
+				Description: "Create a record with a value the local format check does not accept",
+				Command:     "hetznerdns record create --zone example.com --name @ --type TXT --value \"$VALUE\" --skip-validation",
+			},
+			{
+				Description: "Create a record in a script, printing only its ID and nothing but errors on stderr",
+				Command:     "RECORD_ID=$(hetznerdns record create --zone example.com --name www --type A --value 192.168.1.1 -o id --quiet)",
+			},
+			{
+				Description: "Create the records listed in a YAML, JSON or CSV file",
+				Command:     "hetznerdns record create --zone example.com --from-file records.yaml",
+			},
 			{
 				Description: "Update a record",
 				Command:     "hetznerdns record update --id RECORD_ID --zone example.com --value 192.168.1.2",
@@ -140,6 +222,26 @@ func parseExamples(cmdName string) []Example {
 				Command:     "hetznerdns record delete --id RECORD_ID",
 			},
 		}...)
+	case "primary-server":
+		examples = append(examples, []Example{
+			{
+				Description: "List the primary servers of a secondary zone",
+				Command:     "hetznerdns primary-server list --zone example.com",
+			},
+			{
+				Description: "Add a hidden primary server",
+				Command:     "hetznerdns primary-server add --zone example.com --address 192.0.2.53 --port 53",
+			},
+			{
+				Description: "Remove a primary server",
+				Command:     "hetznerdns primary-server remove --id PRIMARY_SERVER_ID",
+			},
+		}...)
+	case "cache":
+		examples = append(examples, Example{
+			Description: "Forget all cached zone IDs",
+			Command:     "hetznerdns cache clear",
+		})
 	case "version":
 		examples = append(examples, Example{
 			Description: "Show version information",
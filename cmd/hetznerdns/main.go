@@ -1,26 +1,198 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/shotgundd/hetznerdns/pkg/api"
+	"github.com/shotgundd/hetznerdns/pkg/config"
 	"github.com/spf13/cobra"
 )
 
+// Values of the global flags
+var (
+	endpoint     string
+	retries      int
+	retryMaxWait time.Duration
+	rateLimit    float64
+	debug        bool
+	harFile      string
+	noCache      bool
+	quiet        bool
+)
+
+// commandStarted is set once the flags and arguments of the command being run
+// have been validated, errors returned before that are usage errors
+var commandStarted bool
+
+// runningCommand is the command being run once it has started, for hints
+// about its errors
+var runningCommand *cobra.Command
+
+// harRecorder records the API requests of this run when --har is given
+var harRecorder *api.HARRecorder
+
 var rootCmd = &cobra.Command{
 	Use:   "hetznerdns",
 	Short: "A CLI tool to manage Hetzner DNS records",
 	Long: `hetznerdns is a command line tool that allows you to create, 
 read, update, and delete DNS records on Hetzner DNS service.`,
+	// Errors are printed by main, to stderr and with a hint
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Cobra checks required flags after this hook, check them here so
+		// that they are reported as usage errors
+		if err := cmd.ValidateRequiredFlags(); err != nil {
+			return &usageError{err: err}
+		}
+		if err := cmd.ValidateFlagGroups(); err != nil {
+			return &usageError{err: err}
+		}
+
+		p, err := newPrinter(outputFlag, os.Stdout)
+		if err != nil {
+			return &usageError{err: err}
+		}
+		output = p
+
+		if quiet {
+			config.Notices = io.Discard
+		}
+		commandStarted = true
+		runningCommand = cmd
+		return nil
+	},
 }
 
 func init() {
-	// Add commands here
+	rootCmd.PersistentFlags().StringVar(&endpoint, "endpoint", "", "Hetzner DNS API base URL (env HETZNER_DNS_ENDPOINT)")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", api.DefaultRetryPolicy.MaxAttempts-1, "Number of times a rate limited or failed request is retried")
+	rootCmd.PersistentFlags().Float64Var(&rateLimit, "rate-limit", 0, "Maximum number of API requests per second (0 for no limit)")
+	rootCmd.PersistentFlags().DurationVar(&retryMaxWait, "retry-max-wait", api.DefaultRetryPolicy.MaxDelay, "Maximum time to wait before a single retry")
+	rootCmd.PersistentFlags().BoolVar(&debug, "debug", false, "Log every API request and response to stderr (env HETZNER_DNS_DEBUG)")
+	rootCmd.PersistentFlags().StringVar(&harFile, "har", "", "Record all API requests and responses of this run in a HAR file")
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", outputTable, "Output format: table, wide, json, yaml, csv, tsv, name, id, template=TEMPLATE or jsonpath=EXPRESSION; templates and JSONPath expressions are applied to each item, e.g. jsonpath='{.id} {.name}'")
+	rootCmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Look up zone IDs from the API instead of the local zone cache")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Only print results and errors, no notices or hints")
+}
+
+// loadClient loads the configuration and creates the API client for a
+// command, failing if no API token is configured
+func loadClient(extra ...api.Option) (api.DNSClient, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, withContext("Error loading config", err)
+	}
+	if cfg.APIToken == "" {
+		return nil, errNoToken
+	}
+	return newClient(cfg, extra...), nil
+}
+
+// notef prints a notice to stderr unless --quiet is set
+func notef(format string, args ...interface{}) {
+	if !quiet {
+		fmt.Fprintf(os.Stderr, format, args...)
+	}
+}
+
+// newClient builds the API client used by the commands. Tests replace it to
+// run commands against a stand-in for the API.
+var newClient = func(cfg *config.Config, extra ...api.Option) api.DNSClient {
+	client := newAPIClient(cfg, extra...)
+
+	cache, err := newZoneCache(apiBaseURL(cfg), cfg.APIToken)
+	if err != nil {
+		return client
+	}
+	return &cachedClient{DNSClient: client, cache: cache, readCache: !noCache}
+}
+
+// apiBaseURL returns the API base URL, letting the --endpoint flag take
+// precedence over HETZNER_DNS_ENDPOINT and the config file
+func apiBaseURL(cfg *config.Config) string {
+	if endpoint != "" {
+		return endpoint
+	}
+	if cfg.Endpoint != "" {
+		return cfg.Endpoint
+	}
+	return api.DefaultBaseURL
+}
+
+// newAPIClient creates an API client from the configuration, letting the
+// --endpoint flag take precedence over HETZNER_DNS_ENDPOINT and the config file.
+// Any extra options are applied last.
+func newAPIClient(cfg *config.Config, extra ...api.Option) *api.Client {
+	retryPolicy := api.DefaultRetryPolicy
+	retryPolicy.MaxAttempts = retries + 1
+	retryPolicy.MaxDelay = retryMaxWait
+
+	opts := []api.Option{
+		api.WithUserAgent("hetznerdns-cli/" + Version),
+		api.WithRetryPolicy(retryPolicy),
+	}
+
+	if rateLimit > 0 {
+		opts = append(opts, api.WithRateLimiter(api.NewRateLimiter(rateLimit, int(rateLimit))))
+	}
+
+	if debug || cfg.Debug {
+		opts = append(opts, api.WithDebugLog(os.Stderr))
+	}
+	if harFile != "" {
+		if harRecorder == nil {
+			harRecorder = api.NewHARRecorder("hetznerdns-cli")
+		}
+		opts = append(opts, api.WithHARRecorder(harRecorder))
+	}
+
+	opts = append(opts, api.WithBaseURL(apiBaseURL(cfg)))
+	opts = append(opts, extra...)
+
+	return api.NewClient(cfg.APIToken, opts...)
 }
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	// Cancel in-flight API requests when the user presses Ctrl-C
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := execute(ctx)
+	saveHAR()
+	if err != nil {
+		printError(os.Stderr, err)
+		stop()
+		os.Exit(exitCode(err))
+	}
+}
+
+// execute runs the command given on the command line. Errors about unknown
+// commands, flags and arguments are returned as usage errors.
+func execute(ctx context.Context) error {
+	commandStarted = false
+	runningCommand = nil
+	err := rootCmd.ExecuteContext(ctx)
+	var usageErr *usageError
+	if err != nil && !commandStarted && !errors.As(err, &usageErr) {
+		return &usageError{err: err}
+	}
+	return err
+}
+
+// saveHAR writes the requests recorded for --har to the HAR file
+func saveHAR() {
+	if harRecorder == nil {
+		return
+	}
+	if err := harRecorder.Save(harFile); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing HAR file: %v\n", err)
 	}
 }
@@ -0,0 +1,480 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats of the global --output flag
+const (
+	outputTable    = "table"
+	outputWide     = "wide"
+	outputJSON     = "json"
+	outputYAML     = "yaml"
+	outputCSV      = "csv"
+	outputTSV      = "tsv"
+	outputName     = "name"
+	outputID       = "id"
+	outputTemplate = "template"
+	outputJSONPath = "jsonpath"
+)
+
+// outputFlag is the value of the global --output flag
+var outputFlag string
+
+// output prints command results in the format selected with --output. It is
+// set up before a command runs.
+var output = &printer{format: outputTable, out: os.Stdout}
+
+// printer writes command results in one of the output formats
+type printer struct {
+	format   string
+	template *template.Template
+	jsonPath *jsonPath
+	out      io.Writer
+}
+
+// newPrinter returns a printer for the value of the --output flag
+func newPrinter(spec string, out io.Writer) (*printer, error) {
+	p := &printer{out: out}
+
+	name, arg, hasArg := strings.Cut(spec, "=")
+	switch name {
+	case outputTable, outputWide, outputJSON, outputYAML, outputCSV, outputTSV, outputName, outputID:
+		if hasArg {
+			return nil, fmt.Errorf("output format %q takes no argument", name)
+		}
+	case outputTemplate:
+		tmpl, err := template.New("output").Option("missingkey=zero").Parse(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template: %w", err)
+		}
+		p.template = tmpl
+	case outputJSONPath:
+		path, err := parseJSONPath(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid jsonpath: %w", err)
+		}
+		p.jsonPath = path
+	default:
+		return nil, fmt.Errorf("unknown output format %q, use table, wide, json, yaml, csv, tsv, name, id, template=TEMPLATE or jsonpath=EXPRESSION", spec)
+	}
+	p.format = name
+	return p, nil
+}
+
+// isTable reports whether results are shown for people rather than scripts
+func (p *printer) isTable() bool {
+	return p.format == outputTable || p.format == outputWide
+}
+
+// deleteResult is printed for a deleted zone, record or primary server
+type deleteResult struct {
+	ID      string `json:"id"`
+	Name    string `json:"name,omitempty"`
+	Deleted bool   `json:"deleted"`
+}
+
+// deleteColumns are the columns of deleteResult tables
+var deleteColumns = []column[deleteResult]{
+	{header: "ID", value: func(r deleteResult) string { return r.ID }},
+	{header: "NAME", value: func(r deleteResult) string { return r.Name }},
+	{header: "DELETED", value: func(r deleteResult) string { return strconv.FormatBool(r.Deleted) }},
+}
+
+// withWide returns a printer for -o wide if wide is set and the output is a
+// table, for the --wide flag of list commands
+func (p *printer) withWide(wide bool) *printer {
+	if !wide || p.format != outputTable {
+		return p
+	}
+	wideP := *p
+	wideP.format = outputWide
+	return &wideP
+}
+
+// column is a column of table, CSV and TSV output
+type column[T any] struct {
+	header string
+	// wide columns are only shown in tables with -o wide, and always in CSV and TSV
+	wide  bool
+	value func(T) string
+}
+
+// printList prints a list of items. Tables show the columns, or the empty
+// message if there are no items.
+func printList[T any](p *printer, items []T, columns []column[T], empty string) error {
+	switch p.format {
+	case outputTable, outputWide:
+		if len(items) == 0 {
+			fmt.Fprintln(p.out, empty)
+			return nil
+		}
+		return p.writeTable(columnValues(items, columns, p.format == outputWide))
+	case outputCSV, outputTSV:
+		return p.writeCSV(columnValues(items, columns, true))
+	case outputJSON:
+		if items == nil {
+			items = []T{}
+		}
+		return p.writeJSON(items)
+	case outputYAML:
+		if items == nil {
+			items = []T{}
+		}
+		return p.writeYAML(items)
+	default:
+		for _, item := range items {
+			if err := writeItem(p, item, columns); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// printItem prints a single item. Tables call show instead, which prints the
+// item or a message about it for people.
+func printItem[T any](p *printer, item T, columns []column[T], show func()) error {
+	switch p.format {
+	case outputTable, outputWide:
+		show()
+		return nil
+	case outputCSV, outputTSV:
+		return p.writeCSV(columnValues([]T{item}, columns, true))
+	case outputJSON:
+		return p.writeJSON(item)
+	case outputYAML:
+		return p.writeYAML(item)
+	default:
+		return writeItem(p, item, columns)
+	}
+}
+
+// writeItem prints an item on its own line in the name, id, template and
+// jsonpath formats
+func writeItem[T any](p *printer, item T, columns []column[T]) error {
+	var line string
+	switch p.format {
+	case outputName, outputID:
+		// Items without a name are printed by ID
+		if line, _ = findColumn(item, columns, strings.ToUpper(p.format)); line == "" {
+			line, _ = findColumn(item, columns, "ID")
+		}
+	case outputTemplate:
+		data, err := genericJSON(item)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := p.template.Execute(&buf, data); err != nil {
+			return fmt.Errorf("error executing template: %w", err)
+		}
+		line = buf.String()
+	case outputJSONPath:
+		data, err := genericJSON(item)
+		if err != nil {
+			return err
+		}
+		if line, err = p.jsonPath.execute(data); err != nil {
+			return err
+		}
+	}
+
+	if !strings.HasSuffix(line, "\n") {
+		line += "\n"
+	}
+	_, err := io.WriteString(p.out, line)
+	return err
+}
+
+// findColumn returns the value of the column with the given header
+func findColumn[T any](item T, columns []column[T], header string) (string, bool) {
+	for _, c := range columns {
+		if c.header == header {
+			return c.value(item), true
+		}
+	}
+	return "", false
+}
+
+// columnValues returns the header and rows of a table
+func columnValues[T any](items []T, columns []column[T], wide bool) [][]string {
+	var header []string
+	var shown []column[T]
+	for _, c := range columns {
+		if c.wide && !wide {
+			continue
+		}
+		header = append(header, c.header)
+		shown = append(shown, c)
+	}
+
+	rows := [][]string{header}
+	for _, item := range items {
+		row := make([]string, len(shown))
+		for i, c := range shown {
+			row[i] = c.value(item)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// writeTable prints rows aligned in columns
+func (p *printer) writeTable(rows [][]string) error {
+	w := tabwriter.NewWriter(p.out, 0, 0, 3, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// writeCSV prints rows as comma or tab separated values
+func (p *printer) writeCSV(rows [][]string) error {
+	w := csv.NewWriter(p.out)
+	if p.format == outputTSV {
+		w.Comma = '\t'
+	}
+	w.WriteAll(rows)
+	return w.Error()
+}
+
+// writeJSON prints v as indented JSON
+func (p *printer) writeJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(p.out, string(data))
+	return err
+}
+
+// writeYAML prints v as YAML, with the field names and order of its JSON
+// encoding
+func (p *printer) writeYAML(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	// JSON is YAML in flow style; switch to block style for readability
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	resetYAMLStyle(&node)
+
+	enc := yaml.NewEncoder(p.out)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// resetYAMLStyle clears the style of node and its children, so that they are
+// encoded in the default block style
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
+
+// genericJSON returns v decoded from its JSON encoding into maps and
+// slices, so that templates and JSONPath expressions use the JSON field names
+func genericJSON(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	return generic, nil
+}
+
+// jsonPath is a parsed JSONPath template like "{.id} {.name}". Text outside
+// of braces is printed as is. A template without braces is a single
+// expression.
+type jsonPath struct {
+	parts []jsonPathPart
+}
+
+// jsonPathPart is literal text or an expression of a jsonPath
+type jsonPathPart struct {
+	text  string
+	steps []jsonPathStep
+	isExp bool
+}
+
+// jsonPathStep selects a field, an index or, with wildcard, all elements
+type jsonPathStep struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// parseJSONPath parses a JSONPath template
+func parseJSONPath(s string) (*jsonPath, error) {
+	if !strings.Contains(s, "{") {
+		s = "{" + s + "}"
+	}
+
+	path := &jsonPath{}
+	for s != "" {
+		start := strings.Index(s, "{")
+		if start < 0 {
+			path.parts = append(path.parts, jsonPathPart{text: s})
+			break
+		}
+		if start > 0 {
+			path.parts = append(path.parts, jsonPathPart{text: s[:start]})
+		}
+		end := strings.Index(s[start:], "}")
+		if end < 0 {
+			return nil, fmt.Errorf("unclosed { in %q", s)
+		}
+		steps, err := parseJSONPathExpression(s[start+1 : start+end])
+		if err != nil {
+			return nil, err
+		}
+		path.parts = append(path.parts, jsonPathPart{steps: steps, isExp: true})
+		s = s[start+end+1:]
+	}
+	return path, nil
+}
+
+// parseJSONPathExpression parses an expression like ".ns[0]" or "$.records[*].name"
+func parseJSONPathExpression(expr string) ([]jsonPathStep, error) {
+	rest := strings.TrimPrefix(strings.TrimSpace(expr), "$")
+	var steps []jsonPathStep
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("empty field name in %q", expr)
+			}
+			steps = append(steps, jsonPathStep{field: rest[:end]})
+			rest = rest[end:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed [ in %q", expr)
+			}
+			inner := rest[1:end]
+			switch {
+			case inner == "*":
+				steps = append(steps, jsonPathStep{wildcard: true})
+			case strings.HasPrefix(inner, "'") && strings.HasSuffix(inner, "'") && len(inner) >= 2:
+				steps = append(steps, jsonPathStep{field: inner[1 : len(inner)-1]})
+			default:
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index %q in %q", inner, expr)
+				}
+				steps = append(steps, jsonPathStep{index: index, isIndex: true})
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("unexpected %q in %q, expressions start with a dot", rest[:1], expr)
+		}
+	}
+	return steps, nil
+}
+
+// execute evaluates the template against data decoded by genericJSON
+func (p *jsonPath) execute(data interface{}) (string, error) {
+	var out strings.Builder
+	for _, part := range p.parts {
+		if !part.isExp {
+			out.WriteString(part.text)
+			continue
+		}
+
+		values := []interface{}{data}
+		for _, step := range part.steps {
+			values = step.apply(values)
+		}
+		for i, value := range values {
+			if i > 0 {
+				out.WriteString(" ")
+			}
+			text, err := formatJSONPathValue(value)
+			if err != nil {
+				return "", err
+			}
+			out.WriteString(text)
+		}
+	}
+	return out.String(), nil
+}
+
+// apply returns the values selected by the step from each of values.
+// Missing fields and indexes select nothing.
+func (s jsonPathStep) apply(values []interface{}) []interface{} {
+	var selected []interface{}
+	for _, value := range values {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if s.wildcard {
+				for _, elem := range v {
+					selected = append(selected, elem)
+				}
+			} else if elem, ok := v[s.field]; ok && !s.isIndex {
+				selected = append(selected, elem)
+			}
+		case []interface{}:
+			switch {
+			case s.wildcard:
+				selected = append(selected, v...)
+			case s.isIndex:
+				index := s.index
+				if index < 0 {
+					index += len(v)
+				}
+				if index >= 0 && index < len(v) {
+					selected = append(selected, v[index])
+				}
+			}
+		}
+	}
+	return selected
+}
+
+// formatJSONPathValue formats a selected value, encoding objects and lists as JSON
+func formatJSONPathValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	default:
+		data, err := json.Marshal(v)
+		return string(data), err
+	}
+}
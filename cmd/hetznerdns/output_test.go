@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/shotgundd/hetznerdns/pkg/api"
+)
+
+func TestPrintListFormats(t *testing.T) {
+	records := []api.Record{
+		{ID: "record1", Name: "www", Type: "A", Value: "192.0.2.1", TTL: 300, ZoneID: "zone1"},
+		{ID: "record2", Name: "@", Type: "TXT", Value: `"v=spf1 -all"`, ZoneID: "zone1"},
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"table", "ID        NAME   TYPE   VALUE           TTL\nrecord1   www    A      192.0.2.1       300\nrecord2   @      TXT    \"v=spf1 -all\"   default\n"},
+		{"csv", "ID,NAME,TYPE,VALUE,TTL,CREATED,MODIFIED\nrecord1,www,A,192.0.2.1,300,-,-\nrecord2,@,TXT,\"\"\"v=spf1 -all\"\"\",default,-,-\n"},
+		{"name", "www\n@\n"},
+		{"id", "record1\nrecord2\n"},
+		{"template={{.name}}={{.value}}", "www=192.0.2.1\n@=\"v=spf1 -all\"\n"},
+		{"jsonpath={.type} {.ttl}", "A 300\nTXT \n"},
+		{"yaml", "- id: record1\n  type: A\n  name: www\n  value: 192.0.2.1\n  ttl: 300\n  zone_id: zone1\n- id: record2\n  type: TXT\n  name: '@'\n  value: '\"v=spf1 -all\"'\n  zone_id: zone1\n"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		p, err := newPrinter(tt.format, &buf)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.format, err)
+		}
+		if err := printList(p, records, recordColumns, "No records"); err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.format, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%s: expected\n%q\ngot\n%q", tt.format, tt.want, buf.String())
+		}
+	}
+}
+
+func TestPrintEmptyList(t *testing.T) {
+	for format, want := range map[string]string{"table": "No records\n", "json": "[]\n", "name": ""} {
+		var buf bytes.Buffer
+		p, _ := newPrinter(format, &buf)
+		if err := printList(p, nil, recordColumns, "No records"); err != nil {
+			t.Fatalf("%s: expected no error, got %v", format, err)
+		}
+		if buf.String() != want {
+			t.Errorf("%s: expected %q, got %q", format, want, buf.String())
+		}
+	}
+}
+
+func TestNewPrinterErrors(t *testing.T) {
+	for _, spec := range []string{"xml", "json=x", "template={{.name", "jsonpath={.name", "jsonpath={name}"} {
+		if _, err := newPrinter(spec, &bytes.Buffer{}); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+func TestJSONPath(t *testing.T) {
+	data := map[string]interface{}{
+		"name": "example.com",
+		"ns":   []interface{}{"a.example", "b.example"},
+		"txt_verification": map[string]interface{}{
+			"token": "secret",
+		},
+	}
+
+	tests := map[string]string{
+		".name":                     "example.com",
+		"$.ns[1]":                   "b.example",
+		"{.ns[-1]}":                 "b.example",
+		"{.ns[*]}":                  "a.example b.example",
+		"{.ns}":                     `["a.example","b.example"]`,
+		"{.txt_verification.token}": "secret",
+		"{.name}: {.missing}":       "example.com: ",
+	}
+	for expr, want := range tests {
+		path, err := parseJSONPath(expr)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", expr, err)
+		}
+		got, err := path.execute(data)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", expr, err)
+		}
+		if got != want {
+			t.Errorf("%s: expected %q, got %q", expr, want, got)
+		}
+	}
+}
+
+func TestWithWide(t *testing.T) {
+	if p := (&printer{format: outputTable}).withWide(true); p.format != outputWide {
+		t.Errorf("Expected --wide to select wide tables, got %s", p.format)
+	}
+	if p := (&printer{format: outputJSON}).withWide(true); p.format != outputJSON {
+		t.Errorf("Expected --wide not to change JSON output, got %s", p.format)
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/shotgundd/hetznerdns/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// addPageFlags adds the --page and --per-page flags used by list commands
+func addPageFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("page", 0, "Fetch only this page instead of all pages")
+	cmd.Flags().Int("per-page", api.DefaultPerPage, "Number of entries requested per page")
+}
+
+// getPageFlags returns the values of the --page and --per-page flags
+func getPageFlags(cmd *cobra.Command) (int, int) {
+	page, _ := cmd.Flags().GetInt("page")
+	perPage, _ := cmd.Flags().GetInt("per-page")
+	return page, perPage
+}
+
+// printPagination prints a footer describing the fetched page, if only a
+// single page was requested
+func printPagination(p *api.Pagination) {
+	if p == nil {
+		return
+	}
+	fmt.Printf("\nPage %d of %d (%d total)\n", p.Page, p.LastPage, p.TotalEntries)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/shotgundd/hetznerdns/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(primaryServerCmd)
+	primaryServerCmd.AddCommand(primaryServerListCmd)
+	primaryServerCmd.AddCommand(primaryServerAddCmd)
+	primaryServerCmd.AddCommand(primaryServerRemoveCmd)
+
+	// Flags for primary-server list command
+	primaryServerListCmd.Flags().StringP("zone", "z", "", "Zone name like example.com or zone ID; values without a dot are used as IDs (required)")
+	primaryServerListCmd.MarkFlagRequired("zone")
+
+	// Flags for primary-server add command
+	primaryServerAddCmd.Flags().StringP("zone", "z", "", "Zone name like example.com or zone ID; values without a dot are used as IDs (required)")
+	primaryServerAddCmd.Flags().StringP("address", "a", "", "IPv4 or IPv6 address of the primary server (required)")
+	primaryServerAddCmd.Flags().IntP("port", "p", 53, "Port the primary server answers zone transfers on")
+	primaryServerAddCmd.MarkFlagRequired("zone")
+	primaryServerAddCmd.MarkFlagRequired("address")
+
+	// Flags for primary-server remove command
+	primaryServerRemoveCmd.Flags().StringP("id", "i", "", "Primary server ID (required)")
+	primaryServerRemoveCmd.MarkFlagRequired("id")
+}
+
+var primaryServerCmd = &cobra.Command{
+	Use:   "primary-server",
+	Short: "Manage primary servers of secondary zones",
+	Long: `List, add, and remove the primary name servers a secondary zone is
+transferred from, e.g. a hidden primary you operate yourself.`,
+}
+
+var primaryServerListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List primary servers",
+	Long:  `List the primary servers of a secondary zone.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		zoneIDOrName, _ := cmd.Flags().GetString("zone")
+
+		client, err := loadClient()
+		if err != nil {
+			return err
+		}
+
+		// Resolve zone ID from name if needed
+		zoneID, err := resolveZoneID(cmd.Context(), client, zoneIDOrName)
+		if err != nil {
+			return withContext("Error resolving zone", err)
+		}
+
+		servers, err := client.GetPrimaryServersContext(cmd.Context(), zoneID)
+		if err != nil {
+			return withContext("Error fetching primary servers", err)
+		}
+
+		if err := printList(output, servers, primaryServerColumns, "No primary servers found for this zone."); err != nil {
+			return withContext("Error printing primary servers", err)
+		}
+		return nil
+	},
+}
+
+// primaryServerColumns are the columns of primary server tables
+var primaryServerColumns = []column[api.PrimaryServer]{
+	{header: "ID", value: func(s api.PrimaryServer) string { return s.ID }},
+	{header: "ADDRESS", value: func(s api.PrimaryServer) string { return s.Address }},
+	{header: "PORT", value: func(s api.PrimaryServer) string { return strconv.Itoa(s.Port) }},
+	{header: "CREATED", value: func(s api.PrimaryServer) string { return formatTime(s.Created) }},
+	{header: "MODIFIED", value: func(s api.PrimaryServer) string { return formatTime(s.Modified) }},
+}
+
+var primaryServerAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a primary server",
+	Long:  `Add a primary server to a secondary zone.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		zoneIDOrName, _ := cmd.Flags().GetString("zone")
+		address, _ := cmd.Flags().GetString("address")
+		port, _ := cmd.Flags().GetInt("port")
+
+		client, err := loadClient()
+		if err != nil {
+			return err
+		}
+
+		// Resolve zone ID from name if needed
+		zoneID, err := resolveZoneID(cmd.Context(), client, zoneIDOrName)
+		if err != nil {
+			return withContext("Error resolving zone", err)
+		}
+
+		server, err := client.CreatePrimaryServerContext(cmd.Context(), api.PrimaryServer{
+			Address: address,
+			Port:    port,
+			ZoneID:  zoneID,
+		})
+		if err != nil {
+			return withContext("Error adding primary server", err)
+		}
+
+		err = printItem(output, *server, primaryServerColumns, func() {
+			fmt.Printf("Primary server added successfully with ID: %s\n", server.ID)
+		})
+		if err != nil {
+			return withContext("Error printing primary server", err)
+		}
+		return nil
+	},
+}
+
+var primaryServerRemoveCmd = &cobra.Command{
+	Use:   "remove",
+	Short: "Remove a primary server",
+	Long:  `Remove a primary server from its secondary zone.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, _ := cmd.Flags().GetString("id")
+
+		client, err := loadClient()
+		if err != nil {
+			return err
+		}
+		if err := client.DeletePrimaryServerContext(cmd.Context(), id); err != nil {
+			return withContext("Error removing primary server", err)
+		}
+
+		err = printItem(output, deleteResult{ID: id, Deleted: true}, deleteColumns, func() {
+			fmt.Println("Primary server removed successfully.")
+		})
+		if err != nil {
+			return withContext("Error printing result", err)
+		}
+		return nil
+	},
+}
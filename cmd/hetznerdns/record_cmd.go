@@ -1,46 +1,66 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/shotgundd/hetznerdns/pkg/api"
-	"github.com/shotgundd/hetznerdns/pkg/config"
 	"github.com/spf13/cobra"
 )
 
 func init() {
 	rootCmd.AddCommand(recordCmd)
 	recordCmd.AddCommand(recordListCmd)
+	recordCmd.AddCommand(recordGetCmd)
 	recordCmd.AddCommand(recordCreateCmd)
 	recordCmd.AddCommand(recordUpdateCmd)
 	recordCmd.AddCommand(recordDeleteCmd)
 
 	// Flags for record list command
-	recordListCmd.Flags().StringP("zone", "z", "", "Zone ID or name (required)")
+	recordListCmd.Flags().StringP("zone", "z", "", "Zone name like example.com or zone ID; values without a dot are used as IDs (required)")
 	recordListCmd.MarkFlagRequired("zone")
+	recordListCmd.Flags().BoolP("wide", "w", false, "Show when records were created and last modified")
+	recordListCmd.Flags().String("modified-since", "", "Only show records modified within this duration (e.g. 24h) or since this date (e.g. 2024-01-31)")
+	recordListCmd.Flags().StringSliceP("type", "t", nil, "Only show records of these types, e.g. A,AAAA")
+	recordListCmd.Flags().StringP("name", "n", "", "Only show records with this name, a glob like '_dmarc*' or a regular expression like '/^mail/'")
+	recordListCmd.Flags().StringP("value", "v", "", "Only show records whose value contains this text or IP address as a whole, has an IP address in this network like 10.0.0.0/8, or matches a regular expression like '/^10 /'")
+	recordListCmd.Flags().String("ttl", "", "Only show records with this TTL, a comparison like '<3600' or 'default'")
+	recordListCmd.Flags().String("sort-by", "", "Sort by "+strings.Join(api.RecordSortKeys, ", ")+"; comma-separated, '-' before a key for descending order")
+	addPageFlags(recordListCmd)
+
+	// Flags for record get command
+	recordGetCmd.Flags().StringP("id", "i", "", "Record ID (required)")
+	recordGetCmd.MarkFlagRequired("id")
 
 	// Flags for record create command
-	recordCreateCmd.Flags().StringP("zone", "z", "", "Zone ID or name (required)")
+	recordCreateCmd.Flags().StringP("zone", "z", "", "Zone name like example.com or zone ID; values without a dot are used as IDs (required unless every record in --from-file names its zone)")
 	recordCreateCmd.Flags().StringP("name", "n", "", "Record name (required)")
 	recordCreateCmd.Flags().StringP("type", "t", "", "Record type (A, AAAA, CNAME, MX, TXT, etc.) (required)")
 	recordCreateCmd.Flags().StringP("value", "v", "", "Record value (required)")
 	recordCreateCmd.Flags().IntP("ttl", "", 0, "Time to live in seconds (optional)")
-	recordCreateCmd.MarkFlagRequired("zone")
-	recordCreateCmd.MarkFlagRequired("name")
-	recordCreateCmd.MarkFlagRequired("type")
-	recordCreateCmd.MarkFlagRequired("value")
+	recordCreateCmd.Flags().StringP("from-file", "f", "", "Create the records listed in a JSON, YAML or CSV file")
+	recordCreateCmd.Flags().Bool("skip-validation", false, "Send record values to the API without checking their format first")
+	recordCreateCmd.MarkFlagsRequiredTogether("name", "type", "value")
+	recordCreateCmd.MarkFlagsOneRequired("name", "from-file")
+	recordCreateCmd.MarkFlagsMutuallyExclusive("name", "from-file")
+	recordCreateCmd.MarkFlagsMutuallyExclusive("type", "from-file")
+	recordCreateCmd.MarkFlagsMutuallyExclusive("value", "from-file")
+	recordCreateCmd.MarkFlagsMutuallyExclusive("ttl", "from-file")
 
 	// Flags for record update command
 	recordUpdateCmd.Flags().StringP("id", "i", "", "Record ID (required)")
-	recordUpdateCmd.Flags().StringP("zone", "z", "", "Zone ID or name (required)")
+	recordUpdateCmd.Flags().StringP("zone", "z", "", "Zone name like example.com or zone ID; values without a dot are used as IDs (required)")
 	recordUpdateCmd.Flags().StringP("name", "n", "", "Record name")
 	recordUpdateCmd.Flags().StringP("type", "t", "", "Record type (A, AAAA, CNAME, MX, TXT, etc.)")
 	recordUpdateCmd.Flags().StringP("value", "v", "", "Record value")
 	recordUpdateCmd.Flags().IntP("ttl", "", 0, "Time to live in seconds")
+	recordUpdateCmd.Flags().Bool("skip-validation", false, "Send the record value to the API without checking its format first")
 	recordUpdateCmd.MarkFlagRequired("id")
 	recordUpdateCmd.MarkFlagRequired("zone")
 
@@ -55,128 +75,219 @@ var recordCmd = &cobra.Command{
 	Long:  `Create, list, update, and delete DNS records.`,
 }
 
-// resolveZoneID tries to resolve a zone ID from either an ID or a name
-func resolveZoneID(client *api.Client, zoneIDOrName string) (string, error) {
-	// Try to resolve it as a name first
-	fmt.Printf("Attempting to resolve '%s' as a zone name...\n", zoneIDOrName)
-
-	// Get all zones to check against
-	zones, err := client.GetZones()
-	if err != nil {
-		fmt.Printf("Error fetching zones: %v\n", err)
-		return "", err
+// resolveZoneID returns the ID of a zone given by ID or name. Zone names
+// always contain a dot and zone IDs never do, so IDs are used as given and
+// names are looked up through the zone cache or the API's name filter.
+func resolveZoneID(ctx context.Context, client api.DNSClient, zoneIDOrName string) (string, error) {
+	if !strings.Contains(zoneIDOrName, ".") {
+		return zoneIDOrName, nil
 	}
-
-	// First check if it's an exact match for a zone ID
-	for _, zone := range zones {
-		if zone.ID == zoneIDOrName {
-			fmt.Printf("Found exact match for zone ID: %s (Name: %s)\n", zone.ID, zone.Name)
-			return zone.ID, nil
-		}
-	}
-
-	// Then check if it matches a zone name
-	normalizedInput := strings.ToLower(strings.TrimSuffix(zoneIDOrName, "."))
-	for _, zone := range zones {
-		zoneName := strings.ToLower(strings.TrimSuffix(zone.Name, "."))
-		if zoneName == normalizedInput {
-			fmt.Printf("Found zone with name '%s', ID: %s\n", zone.Name, zone.ID)
-			return zone.ID, nil
-		}
-	}
-
-	// No match found
-	fmt.Printf("Could not find any zone matching '%s'\n", zoneIDOrName)
-
-	// Print available zones to help debugging
-	fmt.Println("\nAvailable zones:")
-	for _, zone := range zones {
-		fmt.Printf("- %s (ID: %s)\n", zone.Name, zone.ID)
-	}
-
-	return "", fmt.Errorf("could not find zone with ID or name '%s'", zoneIDOrName)
+	return client.GetZoneIDByNameContext(ctx, zoneIDOrName)
 }
 
 var recordListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List DNS records",
-	Long:  `List all DNS records for a specific zone.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `List the DNS records of a zone.
+
+The records can be narrowed down by type, name, value and TTL. Names are
+matched exactly, as a glob if they contain *, ? or [, or as a regular
+expression if enclosed in slashes. Values match if they contain the given
+text as a whole, not as part of a longer name or address, are an IP address
+in a given network like 10.0.0.0/8, or match a regular expression enclosed
+in slashes. All matching ignores case.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		zoneIDOrName, _ := cmd.Flags().GetString("zone")
+		wide, _ := cmd.Flags().GetBool("wide")
+		modifiedSince, _ := cmd.Flags().GetString("modified-since")
+		sortBy, _ := cmd.Flags().GetString("sort-by")
+
+		filter := api.RecordFilter{}
+		filter.Types, _ = cmd.Flags().GetStringSlice("type")
+		filter.Name, _ = cmd.Flags().GetString("name")
+		filter.Value, _ = cmd.Flags().GetString("value")
+		filter.TTL, _ = cmd.Flags().GetString("ttl")
+		if modifiedSince != "" {
+			since, err := parseSince(modifiedSince, time.Now())
+			if err != nil {
+				return usageErrorf("invalid --modified-since: %v", err)
+			}
+			filter.ModifiedSince = since
+		}
 
-		cfg, err := config.LoadConfig()
+		matcher, err := api.NewRecordMatcher(filter)
 		if err != nil {
-			fmt.Printf("Error loading config: %v\n", err)
-			return
+			return &usageError{err: err}
 		}
-
-		if cfg.APIToken == "" {
-			fmt.Println("API token not set. Please run 'hetznerdns config set' to configure your API token.")
-			return
+		// Check the sort keys before any request is sent
+		if sortBy != "" {
+			if err := api.SortRecords(nil, sortBy); err != nil {
+				return &usageError{err: err}
+			}
 		}
 
-		client := api.NewClient(cfg.APIToken)
+		page, perPage := getPageFlags(cmd)
+		client, err := loadClient(api.WithPerPage(perPage))
+		if err != nil {
+			return err
+		}
 
 		// Resolve zone ID from name if needed
-		zoneID, err := resolveZoneID(client, zoneIDOrName)
+		zoneID, err := resolveZoneID(cmd.Context(), client, zoneIDOrName)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
+			return withContext("Error resolving zone", err)
 		}
 
-		records, err := client.GetRecords(zoneID)
-		if err != nil {
-			fmt.Printf("Error fetching records: %v\n", err)
-			return
+		var records []api.Record
+		var pagination *api.Pagination
+		if page > 0 {
+			resp, err := client.GetRecordsPageContext(cmd.Context(), zoneID, page, perPage)
+			if err != nil {
+				return withContext("Error fetching records", err)
+			}
+			records = resp.Records
+			pagination = &resp.Meta.Pagination
+		} else {
+			records, err = client.GetRecordsContext(cmd.Context(), zoneID)
+			if err != nil {
+				return withContext("Error fetching records", err)
+			}
 		}
 
-		if len(records) == 0 {
-			fmt.Println("No records found for this zone.")
-			return
+		records = matcher.Filter(records)
+		if sortBy != "" {
+			api.SortRecords(records, sortBy)
 		}
 
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "ID\tNAME\tTYPE\tVALUE\tTTL")
-		for _, record := range records {
-			ttl := strconv.Itoa(record.TTL)
-			if record.TTL == 0 {
-				ttl = "default"
-			}
-			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", record.ID, record.Name, record.Type, record.Value, ttl)
+		p := output.withWide(wide)
+		if err := printList(p, records, recordColumns, "No records found for this zone."); err != nil {
+			return withContext("Error printing records", err)
 		}
-		w.Flush()
+		if p.isTable() && len(records) > 0 {
+			printPagination(pagination)
+		}
+		return nil
 	},
 }
 
+// recordColumns are the columns of record tables
+var recordColumns = []column[api.Record]{
+	{header: "ID", value: func(r api.Record) string { return r.ID }},
+	{header: "NAME", value: func(r api.Record) string { return r.Name }},
+	{header: "TYPE", value: func(r api.Record) string { return r.Type }},
+	{header: "VALUE", value: func(r api.Record) string { return r.Value }},
+	{header: "TTL", value: func(r api.Record) string { return formatTTL(r.TTL) }},
+	{header: "CREATED", wide: true, value: func(r api.Record) string { return formatTime(r.Created) }},
+	{header: "MODIFIED", wide: true, value: func(r api.Record) string { return formatTime(r.Modified) }},
+}
+
+// formatTTL formats a record TTL, using "default" for records that use the
+// TTL of their zone
+func formatTTL(ttl int) string {
+	if ttl == 0 {
+		return "default"
+	}
+	return strconv.Itoa(ttl)
+}
+
+// parseSince parses the value of --modified-since, either a duration before
+// now or a date given as YYYY-MM-DD or in RFC 3339 format
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, time.Local); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("%q is neither a duration like 24h nor a date like 2024-01-31", value)
+}
+
+var recordGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Show a DNS record",
+	Long:  `Show all fields of a single DNS record, including when it was created and last modified.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		recordID, _ := cmd.Flags().GetString("id")
+
+		client, err := loadClient()
+		if err != nil {
+			return err
+		}
+		record, err := client.GetRecordContext(cmd.Context(), recordID)
+		if err != nil {
+			return withContext("Error fetching record", err)
+		}
+
+		if err := printItem(output, *record, recordColumns, func() { printRecord(os.Stdout, record) }); err != nil {
+			return withContext("Error printing record", err)
+		}
+		return nil
+	},
+}
+
+// printRecord writes the details of a record as a list of fields
+func printRecord(out io.Writer, record *api.Record) {
+	w := tabwriter.NewWriter(out, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(w, "ID:\t%s\n", record.ID)
+	fmt.Fprintf(w, "Zone ID:\t%s\n", record.ZoneID)
+	fmt.Fprintf(w, "Name:\t%s\n", record.Name)
+	fmt.Fprintf(w, "Type:\t%s\n", record.Type)
+	fmt.Fprintf(w, "Value:\t%s\n", record.Value)
+	fmt.Fprintf(w, "TTL:\t%s\n", formatTTL(record.TTL))
+	fmt.Fprintf(w, "Created:\t%s\n", formatTime(record.Created))
+	fmt.Fprintf(w, "Modified:\t%s\n", formatTime(record.Modified))
+	w.Flush()
+}
+
 var recordCreateCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a DNS record",
-	Long:  `Create a new DNS record in a specific zone.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `Create a new DNS record in a specific zone.
+
+The value is checked against the format of its record type before it is sent,
+e.g. MX values need a priority and a mail server ("10 mail.example.com").
+Use --skip-validation to leave the check to the API.
+
+With --from-file all records listed in a JSON, YAML or CSV file are created
+with a single bulk request. Each record has the fields zone, name, type, value
+and ttl; --zone is used for records that do not name their zone. Records that
+are incomplete or rejected by the API are reported and make the command exit
+with the validation exit code, the remaining records are still created.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		zoneIDOrName, _ := cmd.Flags().GetString("zone")
 		name, _ := cmd.Flags().GetString("name")
 		recordType, _ := cmd.Flags().GetString("type")
 		value, _ := cmd.Flags().GetString("value")
 		ttl, _ := cmd.Flags().GetInt("ttl")
+		fromFile, _ := cmd.Flags().GetString("from-file")
+		skipValidation, _ := cmd.Flags().GetBool("skip-validation")
 
-		cfg, err := config.LoadConfig()
-		if err != nil {
-			fmt.Printf("Error loading config: %v\n", err)
-			return
+		if fromFile == "" && zoneIDOrName == "" {
+			return usageErrorf("required flag \"zone\" not set")
 		}
 
-		if cfg.APIToken == "" {
-			fmt.Println("API token not set. Please run 'hetznerdns config set' to configure your API token.")
-			return
+		if fromFile == "" && !skipValidation {
+			if err := api.ValidateRecordValue(recordType, value); err != nil {
+				return withContext("Invalid record", err)
+			}
 		}
 
-		client := api.NewClient(cfg.APIToken)
+		client, err := loadClient()
+		if err != nil {
+			return err
+		}
+
+		if fromFile != "" {
+			return createRecordsFromFile(cmd.Context(), client, fromFile, zoneIDOrName, !skipValidation)
+		}
 
 		// Resolve zone ID from name if needed
-		zoneID, err := resolveZoneID(client, zoneIDOrName)
+		zoneID, err := resolveZoneID(cmd.Context(), client, zoneIDOrName)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
+			return withContext("Error resolving zone", err)
 		}
 
 		record := api.Record{
@@ -187,54 +298,142 @@ var recordCreateCmd = &cobra.Command{
 			TTL:    ttl,
 		}
 
-		createdRecord, err := client.CreateRecord(record)
+		createdRecord, err := client.CreateRecordContext(cmd.Context(), record)
 		if err != nil {
-			fmt.Printf("Error creating record: %v\n", err)
-			return
+			return withContext("Error creating record", err)
 		}
 
-		fmt.Printf("Record created successfully with ID: %s\n", createdRecord.ID)
+		err = printItem(output, *createdRecord, recordColumns, func() {
+			fmt.Printf("Record created successfully with ID: %s\n", createdRecord.ID)
+		})
+		if err != nil {
+			return withContext("Error printing record", err)
+		}
+		return nil
 	},
 }
 
+// createRecordsFromFile creates the records listed in a record file with a
+// single bulk request and reports the records that were rejected. Unless
+// validate is false, records with malformed values are rejected locally.
+func createRecordsFromFile(ctx context.Context, client api.DNSClient, path, defaultZone string, validate bool) error {
+	entries, err := readRecordFile(path)
+	if err != nil {
+		return withContext("Error reading record file", err)
+	}
+	if len(entries) == 0 {
+		fmt.Fprintln(os.Stderr, "No records found in the record file.")
+		return nil
+	}
+
+	// Check the entries locally first, so that incomplete records are
+	// reported with the reason instead of being sent to the API
+	var records []api.Record
+	var rejected []string
+	zoneIDs := make(map[string]string)
+	for i, entry := range entries {
+		if entry.Zone == "" {
+			entry.Zone = defaultZone
+		}
+
+		missing := entry.missingFields()
+		if entry.Zone == "" {
+			missing = append(missing, "zone")
+		}
+		if len(missing) > 0 {
+			rejected = append(rejected, fmt.Sprintf("record %d (%s %s %s): missing %s",
+				i+1, entry.Name, entry.Type, entry.Value, strings.Join(missing, ", ")))
+			continue
+		}
+		if validate {
+			if err := api.ValidateRecordValue(entry.Type, entry.Value); err != nil {
+				rejected = append(rejected, fmt.Sprintf("record %d (%s %s %s): %v",
+					i+1, entry.Name, entry.Type, entry.Value, err))
+				continue
+			}
+		}
+
+		zoneID, ok := zoneIDs[entry.Zone]
+		if !ok {
+			zoneID, err = resolveZoneID(ctx, client, entry.Zone)
+			if err != nil {
+				return withContext("Error resolving zone", err)
+			}
+			zoneIDs[entry.Zone] = zoneID
+		}
+
+		records = append(records, api.Record{
+			ZoneID: zoneID,
+			Name:   entry.Name,
+			Type:   entry.Type,
+			Value:  entry.Value,
+			TTL:    entry.TTL,
+		})
+	}
+
+	var created []api.Record
+	if len(records) > 0 {
+		result, err := client.CreateRecordsContext(ctx, records)
+		if err != nil {
+			return withContext("Error creating records", err)
+		}
+		created = result.Records
+
+		for _, record := range result.InvalidRecords {
+			rejected = append(rejected, fmt.Sprintf("%s %s %s: rejected by the API as invalid for this record type",
+				record.Name, record.Type, record.Value))
+		}
+	}
+
+	// Rejections are diagnostics and go to stderr, other output formats list
+	// the created records
+	for _, reason := range rejected {
+		fmt.Fprintf(os.Stderr, "Rejected: %s\n", reason)
+	}
+	if output.isTable() {
+		fmt.Printf("Created %d of %d records\n", len(created), len(entries))
+	} else if err := printList(output, created, recordColumns, ""); err != nil {
+		return withContext("Error printing records", err)
+	}
+
+	if len(rejected) > 0 {
+		return withContext("Error creating records", fmt.Errorf("%w: %d records rejected", api.ErrValidation, len(rejected)))
+	}
+	return nil
+}
+
 var recordUpdateCmd = &cobra.Command{
 	Use:   "update",
 	Short: "Update a DNS record",
 	Long:  `Update an existing DNS record.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		recordID, _ := cmd.Flags().GetString("id")
 		zoneIDOrName, _ := cmd.Flags().GetString("zone")
 		name, _ := cmd.Flags().GetString("name")
 		recordType, _ := cmd.Flags().GetString("type")
 		value, _ := cmd.Flags().GetString("value")
 		ttl, _ := cmd.Flags().GetInt("ttl")
+		skipValidation, _ := cmd.Flags().GetBool("skip-validation")
 
-		cfg, err := config.LoadConfig()
+		client, err := loadClient()
 		if err != nil {
-			fmt.Printf("Error loading config: %v\n", err)
-			return
-		}
-
-		if cfg.APIToken == "" {
-			fmt.Println("API token not set. Please run 'hetznerdns config set' to configure your API token.")
-			return
+			return err
 		}
 
-		client := api.NewClient(cfg.APIToken)
-
 		// Resolve zone ID from name if needed
-		zoneID, err := resolveZoneID(client, zoneIDOrName)
+		zoneID, err := resolveZoneID(cmd.Context(), client, zoneIDOrName)
 		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-			return
+			return withContext("Error resolving zone", err)
 		}
 
-		record := api.Record{
-			ID:     recordID,
-			ZoneID: zoneID,
+		// The API replaces the whole record, so start from its current fields
+		// and only change the ones that were provided
+		record, err := client.GetRecordContext(cmd.Context(), recordID)
+		if err != nil {
+			return withContext("Error getting record", err)
 		}
+		record.ZoneID = zoneID
 
-		// Only update fields that were provided
 		if name != "" {
 			record.Name = name
 		}
@@ -248,13 +447,27 @@ var recordUpdateCmd = &cobra.Command{
 			record.TTL = ttl
 		}
 
-		updatedRecord, err := client.UpdateRecord(record)
+		// Only check the value if it changes, so that records the API accepted
+		// in a format the check does not know can still be renamed or get a
+		// new TTL
+		if !skipValidation && (cmd.Flags().Changed("type") || cmd.Flags().Changed("value")) {
+			if err := api.ValidateRecord(*record); err != nil {
+				return withContext("Invalid record", err)
+			}
+		}
+
+		updatedRecord, err := client.UpdateRecordContext(cmd.Context(), *record)
 		if err != nil {
-			fmt.Printf("Error updating record: %v\n", err)
-			return
+			return withContext("Error updating record", err)
 		}
 
-		fmt.Printf("Record updated successfully: %s\n", updatedRecord.ID)
+		err = printItem(output, *updatedRecord, recordColumns, func() {
+			fmt.Printf("Record updated successfully: %s\n", updatedRecord.ID)
+		})
+		if err != nil {
+			return withContext("Error printing record", err)
+		}
+		return nil
 	},
 }
 
@@ -262,27 +475,24 @@ var recordDeleteCmd = &cobra.Command{
 	Use:   "delete",
 	Short: "Delete a DNS record",
 	Long:  `Delete an existing DNS record.`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		recordID, _ := cmd.Flags().GetString("id")
 
-		cfg, err := config.LoadConfig()
+		client, err := loadClient()
 		if err != nil {
-			fmt.Printf("Error loading config: %v\n", err)
-			return
+			return err
 		}
-
-		if cfg.APIToken == "" {
-			fmt.Println("API token not set. Please run 'hetznerdns config set' to configure your API token.")
-			return
+		err = client.DeleteRecordContext(cmd.Context(), recordID)
+		if err != nil {
+			return withContext("Error deleting record", err)
 		}
 
-		client := api.NewClient(cfg.APIToken)
-		err = client.DeleteRecord(recordID)
+		err = printItem(output, deleteResult{ID: recordID, Deleted: true}, deleteColumns, func() {
+			fmt.Println("Record deleted successfully.")
+		})
 		if err != nil {
-			fmt.Printf("Error deleting record: %v\n", err)
-			return
+			return withContext("Error printing result", err)
 		}
-
-		fmt.Println("Record deleted successfully.")
+		return nil
 	},
 }
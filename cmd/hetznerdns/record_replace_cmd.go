@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+
+	"github.com/shotgundd/hetznerdns/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	recordCmd.AddCommand(recordReplaceCmd)
+
+	recordReplaceCmd.Flags().String("from", "", "Value to replace, e.g. an IP address or host name (required)")
+	recordReplaceCmd.Flags().String("to", "", "Replacement value (required)")
+	recordReplaceCmd.Flags().StringSliceP("type", "t", nil, "Only change records of these types, e.g. A,AAAA")
+	recordReplaceCmd.Flags().StringSlice("zones", nil, "Only change records in zones whose name matches one of these globs, e.g. '*.example.com'")
+	recordReplaceCmd.Flags().Int("concurrency", defaultSearchWorkers, "Number of zones searched at the same time")
+	recordReplaceCmd.Flags().BoolP("yes", "y", false, "Apply the changes without asking for confirmation")
+	recordReplaceCmd.Flags().Bool("dry-run", false, "Only show the changes, do not apply them")
+	recordReplaceCmd.Flags().Bool("skip-validation", false, "Send the new values to the API without checking their format first")
+	recordReplaceCmd.MarkFlagRequired("from")
+	recordReplaceCmd.MarkFlagRequired("to")
+	recordReplaceCmd.MarkFlagsMutuallyExclusive("yes", "dry-run")
+}
+
+var recordReplaceCmd = &cobra.Command{
+	Use:   "replace",
+	Short: "Replace a value in the records of all zones",
+	Long: `Replace a value in the records of all zones, e.g. the IP address of a server
+that moved or the target of CNAME records after a host was renamed.
+
+The value given with --from is replaced where it appears as a whole, so
+replacing 192.0.2.1 leaves 192.0.2.10 alone and replacing mail.example.com
+leaves smtp.mail.example.com alone. Case is ignored, and an IP address also
+replaces values that are the same address written differently, like
+2001:db8:0::1 for 2001:db8::1. The planned changes are
+shown as a diff and applied after confirmation, or right away with --yes.
+Records that could not be updated are listed at the end; since updated
+records no longer contain the old value, running the same command again
+retries only the failed ones.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, _ := cmd.Flags().GetString("from")
+		to, _ := cmd.Flags().GetString("to")
+		zonePatterns, _ := cmd.Flags().GetStringSlice("zones")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		yes, _ := cmd.Flags().GetBool("yes")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		skipValidation, _ := cmd.Flags().GetBool("skip-validation")
+
+		filter := api.RecordFilter{}
+		filter.Types, _ = cmd.Flags().GetStringSlice("type")
+
+		if strings.TrimSpace(from) == "" {
+			return usageErrorf("--from must not be empty")
+		}
+		// Only fetch the records record search would find for the value,
+		// unless the matcher would take it for a network or regular expression
+		if !strings.Contains(from, "/") {
+			filter.Value = from
+		}
+		matcher, err := api.NewRecordMatcher(filter)
+		if err != nil {
+			return &usageError{err: err}
+		}
+		if _, err := matchZones(nil, zonePatterns); err != nil {
+			return &usageError{err: err}
+		}
+		if concurrency < 1 {
+			return usageErrorf("--concurrency must be at least 1")
+		}
+
+		client, err := loadClient()
+		if err != nil {
+			return err
+		}
+
+		zones, err := client.GetZonesContext(cmd.Context())
+		if err != nil {
+			return withContext("Error fetching zones", err)
+		}
+		zones, _ = matchZones(zones, zonePatterns)
+
+		// Changing only part of the records would leave a mix of old and new
+		// values, so stop if any zone cannot be searched
+		matches, failures := searchZones(cmd.Context(), client, zones, matcher, concurrency)
+		if len(failures) > 0 {
+			for _, failure := range failures {
+				fmt.Fprintf(os.Stderr, "Error searching zone %s: %v\n", failure.Zone.Name, failure.Err)
+			}
+			return withContext("Error searching records", fmt.Errorf("%d of %d zones could not be searched, nothing was changed: %w",
+				len(failures), len(zones), failures[0].Err))
+		}
+
+		plan := planReplace(matches, from, to)
+		if len(plan) == 0 {
+			if output.isTable() {
+				fmt.Println("No records contain the value, nothing to change.")
+				return nil
+			}
+			return printList(output, []zoneRecord(nil), zoneRecordColumns, "")
+		}
+
+		if !skipValidation {
+			for _, change := range plan {
+				if err := api.ValidateRecordValue(change.Record.Type, change.newValue); err != nil {
+					return withContext(fmt.Sprintf("Invalid new value for %s", change.fqdn()), err)
+				}
+			}
+		}
+
+		// The diff is the result in table output, other formats print the
+		// changed records and show the diff on stderr
+		diff := io.Writer(os.Stdout)
+		if !output.isTable() {
+			diff = os.Stderr
+		}
+		printReplacePlan(diff, plan)
+
+		if dryRun {
+			if output.isTable() {
+				return nil
+			}
+			return printList(output, changedRecords(plan), zoneRecordColumns, "")
+		}
+		if !yes && !confirm(fmt.Sprintf("Update %d records?", len(plan))) {
+			fmt.Fprintln(os.Stderr, "Replace cancelled, nothing was changed.")
+			return nil
+		}
+
+		updated, failed := applyReplace(cmd.Context(), client, plan)
+
+		if output.isTable() {
+			fmt.Printf("Updated %d of %d records.\n", len(updated), len(plan))
+		} else if err := printList(output, updated, zoneRecordColumns, ""); err != nil {
+			return withContext("Error printing records", err)
+		}
+
+		if len(failed) > 0 {
+			for _, f := range failed {
+				fmt.Fprintf(os.Stderr, "Failed: %s %s (%s): %v\n", f.change.fqdn(), f.change.Record.Type, f.change.Record.ID, f.err)
+			}
+			notef("Run the same command again to retry the failed records.\n")
+			return withContext("Error replacing values", fmt.Errorf("%d of %d records could not be updated: %w",
+				len(failed), len(plan), failed[0].err))
+		}
+		return nil
+	},
+}
+
+// recordChange is a planned change of the value of a record
+type recordChange struct {
+	zoneRecord
+	newValue string
+}
+
+// replaceFailure is a change that could not be applied
+type replaceFailure struct {
+	change recordChange
+	err    error
+}
+
+// planReplace returns the changes replacing from with to in the values of
+// records, skipping records that do not contain from as a whole
+func planReplace(records []zoneRecord, from, to string) []recordChange {
+	var plan []recordChange
+	for _, record := range records {
+		if value, n := replaceValue(record.Record.Value, from, to); n > 0 && value != record.Record.Value {
+			plan = append(plan, recordChange{zoneRecord: record, newValue: value})
+		}
+	}
+	return plan
+}
+
+// replaceValue replaces from with to in value like api.ReplaceWhole, except
+// that a value that is the IP address from, even if written differently like
+// 2001:db8:0::1 for 2001:db8::1, is replaced as a whole
+func replaceValue(value, from, to string) (string, int) {
+	if fromIP := net.ParseIP(from); fromIP != nil {
+		if ip := net.ParseIP(strings.TrimSpace(value)); ip != nil {
+			if ip.Equal(fromIP) {
+				return to, 1
+			}
+			return value, 0
+		}
+	}
+	return api.ReplaceWhole(value, from, to)
+}
+
+// printReplacePlan writes the planned changes as a diff of the old and new
+// values of each record
+func printReplacePlan(out io.Writer, plan []recordChange) {
+	zones := make(map[string]bool)
+	for _, change := range plan {
+		zones[change.Zone] = true
+		fmt.Fprintf(out, "%s %s (%s)\n", change.fqdn(), change.Record.Type, change.Record.ID)
+		fmt.Fprintf(out, "- %s\n", change.Record.Value)
+		fmt.Fprintf(out, "+ %s\n", change.newValue)
+	}
+	fmt.Fprintf(out, "\n%d records in %d zones will be changed.\n", len(plan), len(zones))
+}
+
+// changedRecords returns the records of the plan with their new values
+func changedRecords(plan []recordChange) []zoneRecord {
+	records := make([]zoneRecord, len(plan))
+	for i, change := range plan {
+		records[i] = change.zoneRecord
+		records[i].Record.Value = change.newValue
+	}
+	return records
+}
+
+// applyReplace updates the records of the plan one after the other, reporting
+// the progress on stderr, and returns the updated records and the changes
+// that failed. It stops when ctx is cancelled, reporting the remaining
+// changes as failed.
+func applyReplace(ctx context.Context, client api.DNSClient, plan []recordChange) ([]zoneRecord, []replaceFailure) {
+	var updated []zoneRecord
+	var failed []replaceFailure
+	for i, change := range plan {
+		record := change.Record
+		record.Value = change.newValue
+
+		if err := ctx.Err(); err != nil {
+			failed = append(failed, replaceFailure{change: change, err: err})
+			continue
+		}
+		result, err := client.UpdateRecordContext(ctx, record)
+		if err != nil {
+			failed = append(failed, replaceFailure{change: change, err: err})
+			fmt.Fprintf(os.Stderr, "[%d/%d] Failed to update %s %s\n", i+1, len(plan), change.fqdn(), record.Type)
+			continue
+		}
+		updated = append(updated, zoneRecord{Zone: change.Zone, Record: *result})
+		notef("[%d/%d] Updated %s %s\n", i+1, len(plan), change.fqdn(), record.Type)
+	}
+	return updated, failed
+}
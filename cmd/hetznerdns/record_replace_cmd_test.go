@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/shotgundd/hetznerdns/pkg/api"
+)
+
+func TestPlanReplace(t *testing.T) {
+	records := []zoneRecord{
+		{Zone: "example.com", Record: api.Record{ID: "a", Type: "A", Value: "192.0.2.1"}},
+		{Zone: "example.com", Record: api.Record{ID: "a-longer", Type: "A", Value: "192.0.2.10"}},
+		{Zone: "example.com", Record: api.Record{ID: "aaaa", Type: "AAAA", Value: "2001:db8:0::1"}},
+		{Zone: "example.com", Record: api.Record{ID: "aaaa-other", Type: "AAAA", Value: "2001:db8::10"}},
+		{Zone: "example.com", Record: api.Record{ID: "spf", Type: "TXT", Value: `"v=spf1 ip6:2001:db8::1 -all"`}},
+	}
+
+	tests := []struct {
+		from, to string
+		want     map[string]string
+	}{
+		{"192.0.2.1", "198.51.100.7", map[string]string{"a": "198.51.100.7"}},
+		{"2001:db8::1", "2001:db8::2", map[string]string{"aaaa": "2001:db8::2", "spf": `"v=spf1 ip6:2001:db8::2 -all"`}},
+	}
+
+	for _, tt := range tests {
+		plan := planReplace(records, tt.from, tt.to)
+		got := make(map[string]string)
+		for _, change := range plan {
+			got[change.Record.ID] = change.newValue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: expected changes %v, got %v", tt.from, tt.want, got)
+			continue
+		}
+		for id, value := range tt.want {
+			if got[id] != value {
+				t.Errorf("%s: expected %s to become %q, got %q", tt.from, id, value, got[id])
+			}
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/shotgundd/hetznerdns/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	recordCmd.AddCommand(recordSearchCmd)
+
+	recordSearchCmd.Flags().StringP("value", "v", "", "Find records whose value contains this text or IP address as a whole, has an IP address in this network like 10.0.0.0/8, or matches a regular expression like '/^10 /' (required)")
+	recordSearchCmd.Flags().StringSliceP("type", "t", nil, "Only find records of these types, e.g. A,AAAA")
+	recordSearchCmd.Flags().StringSlice("zones", nil, "Only search zones whose name matches one of these globs, e.g. '*.example.com'")
+	recordSearchCmd.Flags().Int("concurrency", defaultSearchWorkers, "Number of zones searched at the same time")
+	recordSearchCmd.Flags().BoolP("wide", "w", false, "Show when records were created and last modified")
+	recordSearchCmd.MarkFlagRequired("value")
+}
+
+var recordSearchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "Search DNS records in all zones",
+	Long: `Search the records of all zones for a value, e.g. to find every record
+that still references a server before it is retired.
+
+Values match if they contain the given text as a whole, so 203.0.113.10
+matches "ip4:203.0.113.10" but not 203.0.113.100, if they are an IP address
+in a network given in CIDR notation, or if they match a regular expression
+enclosed in slashes. The zones are searched concurrently; zones that cannot
+be searched are reported and make the command fail after the matches of the
+other zones are printed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		zonePatterns, _ := cmd.Flags().GetStringSlice("zones")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		wide, _ := cmd.Flags().GetBool("wide")
+
+		filter := api.RecordFilter{}
+		filter.Value, _ = cmd.Flags().GetString("value")
+		filter.Types, _ = cmd.Flags().GetStringSlice("type")
+
+		matcher, err := api.NewRecordMatcher(filter)
+		if err != nil {
+			return &usageError{err: err}
+		}
+		if _, err := matchZones(nil, zonePatterns); err != nil {
+			return &usageError{err: err}
+		}
+		if concurrency < 1 {
+			return usageErrorf("--concurrency must be at least 1")
+		}
+
+		client, err := loadClient()
+		if err != nil {
+			return err
+		}
+
+		zones, err := client.GetZonesContext(cmd.Context())
+		if err != nil {
+			return withContext("Error fetching zones", err)
+		}
+		zones, _ = matchZones(zones, zonePatterns)
+
+		matches, failures := searchZones(cmd.Context(), client, zones, matcher, concurrency)
+		for _, failure := range failures {
+			fmt.Fprintf(os.Stderr, "Error searching zone %s: %v\n", failure.Zone.Name, failure.Err)
+		}
+
+		if err := printList(output.withWide(wide), matches, zoneRecordColumns, "No matching records found."); err != nil {
+			return withContext("Error printing records", err)
+		}
+
+		if len(failures) > 0 {
+			return withContext("Error searching records", fmt.Errorf("%d of %d zones could not be searched: %w",
+				len(failures), len(zones), failures[0].Err))
+		}
+		return nil
+	},
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// recordEntry is a record read from a file given to record create --from-file
+type recordEntry struct {
+	Zone  string `json:"zone" yaml:"zone"`
+	Name  string `json:"name" yaml:"name"`
+	Type  string `json:"type" yaml:"type"`
+	Value string `json:"value" yaml:"value"`
+	TTL   int    `json:"ttl" yaml:"ttl"`
+}
+
+// recordFile is the document form of a JSON or YAML record file; a plain list
+// of records is accepted as well
+type recordFile struct {
+	Records []recordEntry `json:"records" yaml:"records"`
+}
+
+// readRecordFile reads records from a JSON, YAML or CSV file, choosing the
+// format by the file extension
+func readRecordFile(path string) ([]recordEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		return parseRecordDocument(data, json.Unmarshal)
+	case ".yaml", ".yml":
+		return parseRecordDocument(data, yaml.Unmarshal)
+	case ".csv":
+		return parseRecordCSV(strings.NewReader(string(data)))
+	default:
+		return nil, fmt.Errorf("unsupported record file format %q, use .json, .yaml, .yml or .csv", ext)
+	}
+}
+
+// parseRecordDocument decodes either a list of records or an object with a
+// "records" list
+func parseRecordDocument(data []byte, unmarshal func([]byte, interface{}) error) ([]recordEntry, error) {
+	var entries []recordEntry
+	if err := unmarshal(data, &entries); err == nil {
+		return entries, nil
+	}
+
+	var file recordFile
+	if err := unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error parsing record file: %w", err)
+	}
+	return file.Records, nil
+}
+
+// parseRecordCSV decodes a CSV file whose header names the columns, out of
+// zone, name, type, value and ttl
+func parseRecordCSV(r io.Reader) ([]recordEntry, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading CSV header: %w", err)
+	}
+
+	columns := make(map[string]int)
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(column))
+		switch column {
+		case "zone", "name", "type", "value", "ttl":
+			columns[column] = i
+		default:
+			return nil, fmt.Errorf("unknown CSV column %q, expected zone, name, type, value or ttl", column)
+		}
+	}
+
+	field := func(row []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	var entries []recordEntry
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading CSV: %w", err)
+		}
+
+		entry := recordEntry{
+			Zone:  field(row, "zone"),
+			Name:  field(row, "name"),
+			Type:  field(row, "type"),
+			Value: field(row, "value"),
+		}
+		if ttl := field(row, "ttl"); ttl != "" {
+			if entry.TTL, err = strconv.Atoi(ttl); err != nil {
+				return nil, fmt.Errorf("line %d: invalid ttl %q", line, ttl)
+			}
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// missingFields returns the required fields the entry does not set
+func (e recordEntry) missingFields() []string {
+	var missing []string
+	if e.Name == "" {
+		missing = append(missing, "name")
+	}
+	if e.Type == "" {
+		missing = append(missing, "type")
+	}
+	if e.Value == "" {
+		missing = append(missing, "value")
+	}
+	return missing
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/shotgundd/hetznerdns/pkg/api"
+)
+
+// defaultSearchWorkers is the number of zones searched at the same time
+const defaultSearchWorkers = 4
+
+// zoneRecord is a record found in a search across zones, together with the
+// name of its zone
+type zoneRecord struct {
+	Zone   string
+	Record api.Record
+}
+
+// MarshalJSON implements json.Marshaler, adding the zone name as "zone_name"
+// to the fields of the record
+func (r zoneRecord) MarshalJSON() ([]byte, error) {
+	record, err := json.Marshal(r.Record)
+	if err != nil {
+		return nil, err
+	}
+	zone, err := json.Marshal(r.Zone)
+	if err != nil {
+		return nil, err
+	}
+	// Records always have fields, so the zone name is followed by a comma
+	data := append([]byte(`{"zone_name":`), zone...)
+	data = append(data, ',')
+	return append(data, record[1:]...), nil
+}
+
+// fqdn returns the fully qualified name of the record, without trailing dot
+func (r zoneRecord) fqdn() string {
+	if r.Record.Name == "@" || r.Record.Name == "" {
+		return r.Zone
+	}
+	return r.Record.Name + "." + r.Zone
+}
+
+// zoneRecordColumns are the columns of tables of records from several zones
+var zoneRecordColumns = []column[zoneRecord]{
+	{header: "ZONE", value: func(r zoneRecord) string { return r.Zone }},
+	{header: "ID", value: func(r zoneRecord) string { return r.Record.ID }},
+	{header: "NAME", value: func(r zoneRecord) string { return r.fqdn() }},
+	{header: "TYPE", value: func(r zoneRecord) string { return r.Record.Type }},
+	{header: "VALUE", value: func(r zoneRecord) string { return r.Record.Value }},
+	{header: "TTL", value: func(r zoneRecord) string { return formatTTL(r.Record.TTL) }},
+	{header: "CREATED", wide: true, value: func(r zoneRecord) string { return formatTime(r.Record.Created) }},
+	{header: "MODIFIED", wide: true, value: func(r zoneRecord) string { return formatTime(r.Record.Modified) }},
+}
+
+// zoneFailure is a zone whose records could not be fetched
+type zoneFailure struct {
+	Zone api.Zone
+	Err  error
+}
+
+// matchZones returns the zones whose name matches one of the glob patterns,
+// ignoring case. Without patterns all zones are returned.
+func matchZones(zones []api.Zone, patterns []string) ([]api.Zone, error) {
+	if len(patterns) == 0 {
+		return zones, nil
+	}
+	globs := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		globs[i] = strings.ToLower(strings.TrimSuffix(pattern, "."))
+		if _, err := path.Match(globs[i], ""); err != nil {
+			return nil, fmt.Errorf("invalid zone pattern %q: %w", pattern, err)
+		}
+	}
+
+	var matched []api.Zone
+	for _, zone := range zones {
+		name := strings.ToLower(zone.Name)
+		for _, pattern := range globs {
+			if ok, _ := path.Match(pattern, name); ok {
+				matched = append(matched, zone)
+				break
+			}
+		}
+	}
+	return matched, nil
+}
+
+// searchZones fetches the records of zones with up to workers concurrent
+// requests and returns the records matching matcher, in the order of zones.
+// Zones whose records could not be fetched are returned as failures, the
+// other zones are still searched.
+func searchZones(ctx context.Context, client api.DNSClient, zones []api.Zone, matcher *api.RecordMatcher, workers int) ([]zoneRecord, []zoneFailure) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	records := make([][]api.Record, len(zones))
+	errs := make([]error, len(zones))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers && w < len(zones); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				zoneRecords, err := client.GetRecordsContext(ctx, zones[i].ID)
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				records[i] = matcher.Filter(zoneRecords)
+			}
+		}()
+	}
+	for i := range zones {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var matches []zoneRecord
+	var failures []zoneFailure
+	for i, zone := range zones {
+		if errs[i] != nil {
+			failures = append(failures, zoneFailure{Zone: zone, Err: errs[i]})
+			continue
+		}
+		for _, record := range records[i] {
+			matches = append(matches, zoneRecord{Zone: zone.Name, Record: record})
+		}
+	}
+	return matches, failures
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shotgundd/hetznerdns/pkg/api"
+)
+
+// slowClient serves records slowly and counts how many requests run at once
+type slowClient struct {
+	api.DNSClient
+	mu      sync.Mutex
+	running int
+	max     int
+}
+
+func (c *slowClient) GetRecordsContext(ctx context.Context, zoneID string) ([]api.Record, error) {
+	c.mu.Lock()
+	c.running++
+	if c.running > c.max {
+		c.max = c.running
+	}
+	c.mu.Unlock()
+
+	time.Sleep(5 * time.Millisecond)
+
+	c.mu.Lock()
+	c.running--
+	c.mu.Unlock()
+
+	if zoneID == "broken" {
+		return nil, api.ErrServer
+	}
+	return []api.Record{
+		{ID: zoneID + "-a", Name: "www", Type: "A", Value: "10.0.0.1"},
+		{ID: zoneID + "-b", Name: "@", Type: "MX", Value: "10 mail.example.net"},
+	}, nil
+}
+
+func TestSearchZones(t *testing.T) {
+	var zones []api.Zone
+	for _, id := range []string{"z1", "z2", "broken", "z3", "z4", "z5", "z6"} {
+		zones = append(zones, api.Zone{ID: id, Name: id + ".example"})
+	}
+	matcher, err := api.NewRecordMatcher(api.RecordFilter{Value: "10.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	client := &slowClient{}
+	matches, failures := searchZones(context.Background(), client, zones, matcher, 2)
+
+	if client.max > 2 {
+		t.Errorf("Expected at most 2 concurrent requests, got %d", client.max)
+	}
+	if len(failures) != 1 || failures[0].Zone.ID != "broken" || !errors.Is(failures[0].Err, api.ErrServer) {
+		t.Errorf("Expected the broken zone to fail, got %+v", failures)
+	}
+
+	var ids []string
+	for _, match := range matches {
+		ids = append(ids, match.Record.ID)
+	}
+	if want := "z1-a z2-a z3-a z4-a z5-a z6-a"; strings.Join(ids, " ") != want {
+		t.Errorf("Expected matches %s in zone order, got %s", want, strings.Join(ids, " "))
+	}
+}
+
+func TestZoneRecordJSON(t *testing.T) {
+	match := zoneRecord{Zone: "example.com", Record: api.Record{ID: "r1", Name: "www", Type: "A", Value: "192.0.2.1", ZoneID: "z1"}}
+	data, err := json.Marshal(match)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := `{"zone_name":"example.com","id":"r1","type":"A","name":"www","value":"192.0.2.1","zone_id":"z1"}`
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+	if match.fqdn() != "www.example.com" {
+		t.Errorf("Expected www.example.com, got %s", match.fqdn())
+	}
+}
+
+func TestMatchZones(t *testing.T) {
+	zones := []api.Zone{{Name: "example.com"}, {Name: "shop.example.com"}, {Name: "example.org"}}
+
+	matched, err := matchZones(zones, []string{"*.EXAMPLE.com", "example.org."})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(matched) != 2 || matched[0].Name != "shop.example.com" || matched[1].Name != "example.org" {
+		t.Errorf("Unexpected zones %+v", matched)
+	}
+
+	if _, err := matchZones(zones, []string{"[example"}); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
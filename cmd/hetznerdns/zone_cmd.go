@@ -1,18 +1,65 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strconv"
+	"strings"
 	"text/tabwriter"
+	"time"
 
 	"github.com/shotgundd/hetznerdns/pkg/api"
-	"github.com/shotgundd/hetznerdns/pkg/config"
 	"github.com/spf13/cobra"
 )
 
 func init() {
 	rootCmd.AddCommand(zoneCmd)
 	zoneCmd.AddCommand(zoneListCmd)
+	zoneCmd.AddCommand(zoneGetCmd)
+	zoneCmd.AddCommand(zoneCreateCmd)
+	zoneCmd.AddCommand(zoneUpdateCmd)
+	zoneCmd.AddCommand(zoneDeleteCmd)
+	zoneCmd.AddCommand(zoneExportCmd)
+	zoneCmd.AddCommand(zoneImportCmd)
+
+	// Flags for zone list command
+	addPageFlags(zoneListCmd)
+	zoneListCmd.Flags().BoolP("wide", "w", false, "Show status, name servers, verification and timestamps")
+
+	// Flags for zone get command
+	zoneGetCmd.Flags().StringP("zone", "z", "", "Zone name like example.com or zone ID; values without a dot are used as IDs (required)")
+	zoneGetCmd.MarkFlagRequired("zone")
+
+	// Flags for zone create command
+	zoneCreateCmd.Flags().StringP("name", "n", "", "Zone name, e.g. example.com (required)")
+	zoneCreateCmd.Flags().IntP("ttl", "", 0, "Default time to live in seconds (optional)")
+	zoneCreateCmd.MarkFlagRequired("name")
+
+	// Flags for zone update command
+	zoneUpdateCmd.Flags().StringP("zone", "z", "", "Zone name like example.com or zone ID; values without a dot are used as IDs (required)")
+	zoneUpdateCmd.Flags().IntP("ttl", "", 0, "Default time to live in seconds (required)")
+	zoneUpdateCmd.MarkFlagRequired("zone")
+	zoneUpdateCmd.MarkFlagRequired("ttl")
+
+	// Flags for zone delete command
+	zoneDeleteCmd.Flags().StringP("zone", "z", "", "Zone name like example.com or zone ID; values without a dot are used as IDs (required)")
+	zoneDeleteCmd.Flags().String("confirm", "", "Zone name to confirm the deletion without prompting")
+	zoneDeleteCmd.MarkFlagRequired("zone")
+
+	// Flags for zone export command
+	zoneExportCmd.Flags().StringP("zone", "z", "", "Zone name like example.com or zone ID; values without a dot are used as IDs (required)")
+	zoneExportCmd.Flags().StringP("file", "f", "", "Write the zone file to this path instead of stdout")
+	zoneExportCmd.MarkFlagRequired("zone")
+
+	// Flags for zone import command
+	zoneImportCmd.Flags().StringP("zone", "z", "", "Zone name like example.com or zone ID; values without a dot are used as IDs (required)")
+	zoneImportCmd.Flags().StringP("file", "f", "", "Path of the BIND zone file to import (required)")
+	zoneImportCmd.Flags().Bool("validate-only", false, "Only validate the zone file, do not import it")
+	zoneImportCmd.Flags().BoolP("yes", "y", false, "Import without asking for confirmation")
+	zoneImportCmd.MarkFlagRequired("zone")
+	zoneImportCmd.MarkFlagRequired("file")
 }
 
 var zoneCmd = &cobra.Command{
@@ -25,35 +72,401 @@ var zoneListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List DNS zones",
 	Long:  `List all DNS zones in your Hetzner account.`,
-	Run: func(cmd *cobra.Command, args []string) {
-		cfg, err := config.LoadConfig()
+	RunE: func(cmd *cobra.Command, args []string) error {
+		page, perPage := getPageFlags(cmd)
+		client, err := loadClient(api.WithPerPage(perPage))
+		if err != nil {
+			return err
+		}
+
+		var zones []api.Zone
+		var pagination *api.Pagination
+		if page > 0 {
+			resp, err := client.GetZonesPageContext(cmd.Context(), page, perPage)
+			if err != nil {
+				return withContext("Error fetching zones", err)
+			}
+			zones = resp.Zones
+			pagination = &resp.Meta.Pagination
+		} else {
+			zones, err = client.GetZonesContext(cmd.Context())
+			if err != nil {
+				return withContext("Error fetching zones", err)
+			}
+		}
+
+		wide, _ := cmd.Flags().GetBool("wide")
+		p := output.withWide(wide)
+		if err := printList(p, zones, zoneColumns, "No zones found."); err != nil {
+			return withContext("Error printing zones", err)
+		}
+		if p.isTable() && len(zones) > 0 {
+			printPagination(pagination)
+		}
+		return nil
+	},
+}
+
+var zoneGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Show a DNS zone",
+	Long:  `Show the details of a single DNS zone.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		zoneIDOrName, _ := cmd.Flags().GetString("zone")
+
+		client, err := loadClient()
+		if err != nil {
+			return err
+		}
+
+		// Resolve zone ID from name if needed
+		zoneID, err := resolveZoneID(cmd.Context(), client, zoneIDOrName)
+		if err != nil {
+			return withContext("Error resolving zone", err)
+		}
+
+		zone, err := client.GetZoneContext(cmd.Context(), zoneID)
+		if err != nil {
+			return withContext("Error fetching zone", err)
+		}
+
+		if err := printItem(output, *zone, zoneColumns, func() { printZone(os.Stdout, zone) }); err != nil {
+			return withContext("Error printing zone", err)
+		}
+		return nil
+	},
+}
+
+var zoneCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a DNS zone",
+	Long:  `Create a new DNS zone in your Hetzner account.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		name, _ := cmd.Flags().GetString("name")
+		ttl, _ := cmd.Flags().GetInt("ttl")
+
+		client, err := loadClient()
+		if err != nil {
+			return err
+		}
+		zone, err := client.CreateZoneContext(cmd.Context(), name, ttl)
+		if err != nil {
+			return withContext("Error creating zone", err)
+		}
+
+		err = printItem(output, *zone, zoneColumns, func() {
+			fmt.Printf("Zone created successfully with ID: %s\n", zone.ID)
+		})
+		if err != nil {
+			return withContext("Error printing zone", err)
+		}
+		return nil
+	},
+}
+
+var zoneUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update a DNS zone",
+	Long:  `Update the default TTL of an existing DNS zone.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		zoneIDOrName, _ := cmd.Flags().GetString("zone")
+		ttl, _ := cmd.Flags().GetInt("ttl")
+
+		client, err := loadClient()
+		if err != nil {
+			return err
+		}
+
+		// Resolve zone ID from name if needed
+		zoneID, err := resolveZoneID(cmd.Context(), client, zoneIDOrName)
+		if err != nil {
+			return withContext("Error resolving zone", err)
+		}
+
+		// The API requires the zone name on every update
+		zone, err := client.GetZoneContext(cmd.Context(), zoneID)
+		if err != nil {
+			return withContext("Error fetching zone", err)
+		}
+		zone.TTL = ttl
+
+		updatedZone, err := client.UpdateZoneContext(cmd.Context(), *zone)
+		if err != nil {
+			return withContext("Error updating zone", err)
+		}
+
+		err = printItem(output, *updatedZone, zoneColumns, func() {
+			fmt.Printf("Zone updated successfully: %s\n", updatedZone.ID)
+		})
+		if err != nil {
+			return withContext("Error printing zone", err)
+		}
+		return nil
+	},
+}
+
+var zoneDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "Delete a DNS zone",
+	Long: `Delete a DNS zone together with all of its records.
+
+The deletion has to be confirmed by typing the zone name, or by passing it
+with --confirm.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		zoneIDOrName, _ := cmd.Flags().GetString("zone")
+		confirmation, _ := cmd.Flags().GetString("confirm")
+
+		client, err := loadClient()
+		if err != nil {
+			return err
+		}
+
+		// Resolve zone ID from name if needed
+		zoneID, err := resolveZoneID(cmd.Context(), client, zoneIDOrName)
+		if err != nil {
+			return withContext("Error resolving zone", err)
+		}
+
+		zone, err := client.GetZoneContext(cmd.Context(), zoneID)
+		if err != nil {
+			return withContext("Error fetching zone", err)
+		}
+
+		if !cmd.Flags().Changed("confirm") {
+			fmt.Fprintf(os.Stderr, "This will delete zone %s and all of its %d records.\n", zone.Name, zone.RecordsCount)
+			fmt.Fprint(os.Stderr, "Type the zone name to confirm: ")
+			fmt.Scanln(&confirmation)
+		}
+		if !sameZoneName(confirmation, zone.Name) {
+			return withContext("Error deleting zone", errors.New("confirmation does not match the zone name, nothing was deleted"))
+		}
+
+		if err := client.DeleteZoneContext(cmd.Context(), zone.ID); err != nil {
+			return withContext("Error deleting zone", err)
+		}
+
+		err = printItem(output, deleteResult{ID: zone.ID, Name: zone.Name, Deleted: true}, deleteColumns, func() {
+			fmt.Println("Zone deleted successfully.")
+		})
+		if err != nil {
+			return withContext("Error printing result", err)
+		}
+		return nil
+	},
+}
+
+var zoneExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export a DNS zone as a zone file",
+	Long:  `Export a DNS zone as a BIND (RFC 1035) zone file, e.g. for backups or for moving the zone to another provider.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		zoneIDOrName, _ := cmd.Flags().GetString("zone")
+		file, _ := cmd.Flags().GetString("file")
+
+		// Zone files have a single format
+		if !output.isTable() {
+			return usageErrorf("zone export only writes zone files and does not support --output %s, use --file to write the zone file to a path", output.format)
+		}
+
+		client, err := loadClient()
 		if err != nil {
-			fmt.Printf("Error loading config: %v\n", err)
-			return
+			return err
 		}
 
-		if cfg.APIToken == "" {
-			fmt.Println("API token not set. Please run 'hetznerdns config set' to configure your API token.")
-			return
+		// Resolve zone ID from name if needed
+		zoneID, err := resolveZoneID(cmd.Context(), client, zoneIDOrName)
+		if err != nil {
+			return withContext("Error resolving zone", err)
 		}
 
-		client := api.NewClient(cfg.APIToken)
-		zones, err := client.GetZones()
+		zoneFile, err := client.ExportZoneFileContext(cmd.Context(), zoneID)
 		if err != nil {
-			fmt.Printf("Error fetching zones: %v\n", err)
-			return
+			return withContext("Error exporting zone", err)
 		}
 
-		if len(zones) == 0 {
-			fmt.Println("No zones found.")
-			return
+		if file == "" {
+			fmt.Print(zoneFile)
+			return nil
 		}
 
-		w := tabwriter.NewWriter(os.Stdout, 0, 0, 3, ' ', 0)
-		fmt.Fprintln(w, "ID\tNAME\tTTL\tRECORDS")
-		for _, zone := range zones {
-			fmt.Fprintf(w, "%s\t%s\t%d\t%d\n", zone.ID, zone.Name, zone.TTL, zone.RecordsCount)
+		if err := os.WriteFile(file, []byte(zoneFile), 0644); err != nil {
+			return withContext("Error writing zone file", err)
 		}
-		w.Flush()
+		notef("Zone file written to %s\n", file)
+		return nil
 	},
 }
+
+var zoneImportCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import a zone file into a DNS zone",
+	Long: `Import a BIND (RFC 1035) zone file into a DNS zone, replacing all of its records.
+
+The zone file is validated first and the parsed records are shown before
+anything is changed. With --validate-only the command stops after validation
+and exits with a non-zero code if the zone file has errors, which makes it
+usable as a CI check.
+
+With --output formats other than table, the parsed records are shown on
+stderr, and the valid records or, after the import, the zone are printed.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		zoneIDOrName, _ := cmd.Flags().GetString("zone")
+		file, _ := cmd.Flags().GetString("file")
+		validateOnly, _ := cmd.Flags().GetBool("validate-only")
+		yes, _ := cmd.Flags().GetBool("yes")
+
+		zoneFile, err := os.ReadFile(file)
+		if err != nil {
+			return withContext("Error reading zone file", err)
+		}
+
+		client, err := loadClient()
+		if err != nil {
+			return err
+		}
+
+		// Resolve zone ID from name if needed
+		zoneID, err := resolveZoneID(cmd.Context(), client, zoneIDOrName)
+		if err != nil {
+			return withContext("Error resolving zone", err)
+		}
+
+		zone, err := client.GetZoneContext(cmd.Context(), zoneID)
+		if err != nil {
+			return withContext("Error fetching zone", err)
+		}
+
+		validation, err := client.ValidateZoneFileContext(cmd.Context(), string(zoneFile))
+		if err != nil {
+			return withContext("Error validating zone file", err)
+		}
+
+		// The parsed records are the result in table output, other formats
+		// show them on stderr and print the valid records or the zone
+		report := io.Writer(os.Stdout)
+		if !output.isTable() {
+			report = os.Stderr
+		}
+		printZoneFileValidation(report, validation)
+		if len(validation.InvalidRecords) > 0 {
+			return withContext("Error validating zone file", fmt.Errorf("%w: %d invalid records", api.ErrValidation, len(validation.InvalidRecords)))
+		}
+
+		if validateOnly {
+			if output.isTable() {
+				fmt.Println("Zone file is valid.")
+				return nil
+			}
+			if err := printList(output, validation.ValidRecords, recordColumns, ""); err != nil {
+				return withContext("Error printing records", err)
+			}
+			return nil
+		}
+
+		fmt.Fprintf(os.Stderr, "Importing will replace the %d existing records of zone %s with %d records.\n",
+			zone.RecordsCount, zone.Name, len(validation.ValidRecords))
+		if !yes && !confirm("Continue?") {
+			fmt.Fprintln(os.Stderr, "Import cancelled.")
+			return nil
+		}
+
+		importedZone, err := client.ImportZoneFileContext(cmd.Context(), zone.ID, string(zoneFile))
+		if err != nil {
+			return withContext("Error importing zone file", err)
+		}
+
+		err = printItem(output, *importedZone, zoneColumns, func() {
+			fmt.Printf("Zone file imported successfully: %s now has %d records\n", importedZone.Name, importedZone.RecordsCount)
+		})
+		if err != nil {
+			return withContext("Error printing zone", err)
+		}
+		return nil
+	},
+}
+
+// printZoneFileValidation writes the records parsed from a zone file and
+// warnings about records the API rejected
+func printZoneFileValidation(out io.Writer, validation *api.ZoneFileValidation) {
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTYPE\tVALUE\tTTL")
+	for _, record := range validation.ValidRecords {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", record.Name, record.Type, record.Value, formatTTL(record.TTL))
+	}
+	w.Flush()
+
+	for _, record := range validation.InvalidRecords {
+		fmt.Fprintf(out, "Warning: invalid record: %s %s %s\n", record.Name, record.Type, record.Value)
+	}
+
+	fmt.Fprintf(out, "\nParsed %d records: %d valid, %d invalid\n",
+		validation.ParsedRecords, len(validation.ValidRecords), len(validation.InvalidRecords))
+}
+
+// confirm asks a yes/no question and reports whether the user answered yes
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+
+	var answer string
+	fmt.Scanln(&answer)
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// sameZoneName reports whether two zone names are equal, ignoring case and a trailing dot
+func sameZoneName(a, b string) bool {
+	return strings.EqualFold(strings.TrimSuffix(a, "."), strings.TrimSuffix(b, "."))
+}
+
+// zoneColumns are the columns of zone tables
+var zoneColumns = []column[api.Zone]{
+	{header: "ID", value: func(z api.Zone) string { return z.ID }},
+	{header: "NAME", value: func(z api.Zone) string { return z.Name }},
+	{header: "STATUS", wide: true, value: func(z api.Zone) string { return z.Status }},
+	{header: "TTL", value: func(z api.Zone) string { return strconv.Itoa(z.TTL) }},
+	{header: "RECORDS", value: func(z api.Zone) string { return strconv.Itoa(z.RecordsCount) }},
+	{header: "SECONDARY", value: func(z api.Zone) string { return strconv.FormatBool(z.IsSecondaryDNS) }},
+	{header: "NAME SERVERS", wide: true, value: func(z api.Zone) string { return strings.Join(z.NS, ",") }},
+	{header: "VERIFIED", wide: true, value: func(z api.Zone) string { return formatTime(z.Verified) }},
+	{header: "CREATED", wide: true, value: func(z api.Zone) string { return formatTime(z.Created) }},
+	{header: "MODIFIED", wide: true, value: func(z api.Zone) string { return formatTime(z.Modified) }},
+}
+
+// printZone writes the details of a zone as a list of fields
+func printZone(out io.Writer, zone *api.Zone) {
+	w := tabwriter.NewWriter(out, 0, 0, 1, ' ', 0)
+	fmt.Fprintf(w, "ID:\t%s\n", zone.ID)
+	fmt.Fprintf(w, "Name:\t%s\n", zone.Name)
+	fmt.Fprintf(w, "Status:\t%s\n", zone.Status)
+	fmt.Fprintf(w, "TTL:\t%d\n", zone.TTL)
+	fmt.Fprintf(w, "Records:\t%d\n", zone.RecordsCount)
+	fmt.Fprintf(w, "Name servers:\t%s\n", strings.Join(zone.NS, ", "))
+	fmt.Fprintf(w, "Secondary DNS:\t%t\n", zone.IsSecondaryDNS)
+	fmt.Fprintf(w, "Paused:\t%t\n", zone.Paused)
+	fmt.Fprintf(w, "Created:\t%s\n", formatTime(zone.Created))
+	fmt.Fprintf(w, "Modified:\t%s\n", formatTime(zone.Modified))
+	fmt.Fprintf(w, "Verified:\t%s\n", formatTime(zone.Verified))
+	if zone.TxtVerification != nil && zone.TxtVerification.Token != "" {
+		fmt.Fprintf(w, "TXT verification:\t%s TXT %s\n", zone.TxtVerification.Name, zone.TxtVerification.Token)
+	}
+	if zone.LegacyDNSHost != "" || len(zone.LegacyNS) > 0 {
+		fmt.Fprintf(w, "Legacy DNS host:\t%s\n", zone.LegacyDNSHost)
+		fmt.Fprintf(w, "Legacy name servers:\t%s\n", strings.Join(zone.LegacyNS, ", "))
+	}
+	if zone.Project != "" {
+		fmt.Fprintf(w, "Project:\t%s\n", zone.Project)
+	}
+	if zone.Owner != "" {
+		fmt.Fprintf(w, "Owner:\t%s\n", zone.Owner)
+	}
+	w.Flush()
+}
+
+// formatTime formats a timestamp for tables, using "-" for unset values
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format("2006-01-02 15:04:05")
+}
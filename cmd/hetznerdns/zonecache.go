@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shotgundd/hetznerdns/pkg/api"
+	"github.com/shotgundd/hetznerdns/pkg/config"
+)
+
+// zoneCacheTTL is how long a cached zone ID is used before it is looked up again
+const zoneCacheTTL = time.Hour
+
+// zoneCacheFile is the name of the cache file in the config directory
+const zoneCacheFile = "zone-cache.json"
+
+// zoneCacheEntry is a cached zone ID
+type zoneCacheEntry struct {
+	ID       string    `json:"id"`
+	CachedAt time.Time `json:"cached_at"`
+}
+
+// zoneCacheData is the content of the cache file
+type zoneCacheData struct {
+	// Scope identifies the API endpoint and token the IDs belong to
+	Scope string                    `json:"scope"`
+	Zones map[string]zoneCacheEntry `json:"zones"`
+}
+
+// zoneCache maps zone names to IDs on disk, so commands do not have to look
+// up the zone on every run
+type zoneCache struct {
+	path  string
+	scope string
+	now   func() time.Time
+}
+
+// newZoneCache returns the cache of zone IDs for the given endpoint and token
+func newZoneCache(baseURL, token string) (*zoneCache, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256([]byte(baseURL + "\n" + token))
+	return &zoneCache{
+		path:  filepath.Join(dir, zoneCacheFile),
+		scope: hex.EncodeToString(sum[:8]),
+		now:   time.Now,
+	}, nil
+}
+
+// normalizeZoneName returns the key a zone name is cached under
+func normalizeZoneName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// load reads the cache file. A missing, unreadable or foreign cache is empty.
+func (c *zoneCache) load() zoneCacheData {
+	data := zoneCacheData{Scope: c.scope, Zones: map[string]zoneCacheEntry{}}
+
+	content, err := os.ReadFile(c.path)
+	if err != nil {
+		return data
+	}
+	var stored zoneCacheData
+	if err := json.Unmarshal(content, &stored); err != nil || stored.Scope != c.scope || stored.Zones == nil {
+		return data
+	}
+	return stored
+}
+
+// save writes the cache file, replacing it atomically
+func (c *zoneCache) save(data zoneCacheData) error {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path), zoneCacheFile+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), c.path)
+}
+
+// lookup returns the cached ID of the named zone unless it has expired
+func (c *zoneCache) lookup(name string) (string, bool) {
+	entry, ok := c.load().Zones[normalizeZoneName(name)]
+	if !ok || c.now().Sub(entry.CachedAt) > zoneCacheTTL {
+		return "", false
+	}
+	return entry.ID, true
+}
+
+// store caches the IDs of the given zones
+func (c *zoneCache) store(zones ...api.Zone) error {
+	data := c.load()
+	for _, zone := range zones {
+		data.Zones[normalizeZoneName(zone.Name)] = zoneCacheEntry{ID: zone.ID, CachedAt: c.now()}
+	}
+	return c.save(data)
+}
+
+// forget removes the zone with the given ID from the cache
+func (c *zoneCache) forget(zoneID string) error {
+	data := c.load()
+	for name, entry := range data.Zones {
+		if entry.ID == zoneID {
+			delete(data.Zones, name)
+		}
+	}
+	return c.save(data)
+}
+
+// clearZoneCache removes the cache file of every endpoint and token
+func clearZoneCache() error {
+	dir, err := config.Dir()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(filepath.Join(dir, zoneCacheFile)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// cachedClient looks up zone IDs in a zoneCache before asking the API, and
+// keeps the cache up to date with the zones it sees created, listed and
+// deleted. Failing to update the cache does not fail the request.
+//
+// A cached ID the API does not know, because the zone was deleted and
+// created again elsewhere, is removed from the cache, and the request is
+// repeated with the ID of the zone looked up by name again.
+type cachedClient struct {
+	api.DNSClient
+	cache *zoneCache
+	// readCache is false when --no-cache is given; the cache is still updated
+	readCache bool
+
+	mu sync.Mutex
+	// cachedNames maps the zone IDs taken from the cache to the zone names
+	cachedNames map[string]string
+	// renewed maps stale cached zone IDs to the IDs looked up again
+	renewed map[string]string
+}
+
+// GetZoneIDByNameContext returns the cached zone ID, or looks it up and caches it
+func (c *cachedClient) GetZoneIDByNameContext(ctx context.Context, name string) (string, error) {
+	if c.readCache {
+		if id, ok := c.cache.lookup(name); ok {
+			c.mu.Lock()
+			if c.cachedNames == nil {
+				c.cachedNames = make(map[string]string)
+			}
+			c.cachedNames[id] = name
+			c.mu.Unlock()
+			return id, nil
+		}
+	}
+
+	zone, err := c.DNSClient.GetZoneByNameContext(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	c.cache.store(*zone)
+	return zone.ID, nil
+}
+
+// GetZonesContext lists all zones and caches their IDs
+func (c *cachedClient) GetZonesContext(ctx context.Context) ([]api.Zone, error) {
+	zones, err := c.DNSClient.GetZonesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.store(zones...)
+	return zones, nil
+}
+
+// CreateZoneContext creates a zone and caches its ID
+func (c *cachedClient) CreateZoneContext(ctx context.Context, name string, ttl int) (*api.Zone, error) {
+	zone, err := c.DNSClient.CreateZoneContext(ctx, name, ttl)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.store(*zone)
+	return zone, nil
+}
+
+// DeleteZoneContext deletes a zone and removes it from the cache
+func (c *cachedClient) DeleteZoneContext(ctx context.Context, zoneID string) error {
+	return c.withZoneID(ctx, zoneID, func(zoneID string) error {
+		if err := c.DNSClient.DeleteZoneContext(ctx, zoneID); err != nil {
+			return err
+		}
+		c.cache.forget(zoneID)
+		return nil
+	})
+}
+
+// withZoneID calls call with zoneID. If the ID came from the cache and the
+// API answers that the zone does not exist, or rejects a new object in it,
+// the ID is removed from the cache and call is repeated with the ID of the
+// zone looked up by name again, which is then used for the rest of the run.
+func (c *cachedClient) withZoneID(ctx context.Context, zoneID string, call func(zoneID string) error) error {
+	c.mu.Lock()
+	if renewed, ok := c.renewed[zoneID]; ok {
+		zoneID = renewed
+	}
+	name, cached := c.cachedNames[zoneID]
+	c.mu.Unlock()
+
+	err := call(zoneID)
+	if !cached || !(errors.Is(err, api.ErrNotFound) || errors.Is(err, api.ErrValidation)) {
+		return err
+	}
+
+	c.cache.forget(zoneID)
+	zone, lookupErr := c.DNSClient.GetZoneByNameContext(ctx, name)
+	if lookupErr != nil || zone.ID == zoneID {
+		return err
+	}
+	c.cache.store(*zone)
+
+	c.mu.Lock()
+	delete(c.cachedNames, zoneID)
+	if c.renewed == nil {
+		c.renewed = make(map[string]string)
+	}
+	c.renewed[zoneID] = zone.ID
+	c.mu.Unlock()
+	return call(zone.ID)
+}
+
+// GetZoneContext gets a zone, looking it up again if its cached ID is stale
+func (c *cachedClient) GetZoneContext(ctx context.Context, zoneID string) (*api.Zone, error) {
+	var zone *api.Zone
+	err := c.withZoneID(ctx, zoneID, func(zoneID string) (err error) {
+		zone, err = c.DNSClient.GetZoneContext(ctx, zoneID)
+		return err
+	})
+	return zone, err
+}
+
+// UpdateZoneContext updates a zone, looking it up again if its cached ID is stale
+func (c *cachedClient) UpdateZoneContext(ctx context.Context, zone api.Zone) (*api.Zone, error) {
+	var updated *api.Zone
+	err := c.withZoneID(ctx, zone.ID, func(zoneID string) (err error) {
+		zone.ID = zoneID
+		updated, err = c.DNSClient.UpdateZoneContext(ctx, zone)
+		return err
+	})
+	return updated, err
+}
+
+// ExportZoneFileContext exports a zone, looking it up again if its cached ID is stale
+func (c *cachedClient) ExportZoneFileContext(ctx context.Context, zoneID string) (string, error) {
+	var zoneFile string
+	err := c.withZoneID(ctx, zoneID, func(zoneID string) (err error) {
+		zoneFile, err = c.DNSClient.ExportZoneFileContext(ctx, zoneID)
+		return err
+	})
+	return zoneFile, err
+}
+
+// ImportZoneFileContext imports a zone file, looking the zone up again if its
+// cached ID is stale
+func (c *cachedClient) ImportZoneFileContext(ctx context.Context, zoneID, zoneFile string) (*api.Zone, error) {
+	var zone *api.Zone
+	err := c.withZoneID(ctx, zoneID, func(zoneID string) (err error) {
+		zone, err = c.DNSClient.ImportZoneFileContext(ctx, zoneID, zoneFile)
+		return err
+	})
+	return zone, err
+}
+
+// GetRecordsContext lists the records of a zone, looking it up again if its
+// cached ID is stale
+func (c *cachedClient) GetRecordsContext(ctx context.Context, zoneID string) ([]api.Record, error) {
+	var records []api.Record
+	err := c.withZoneID(ctx, zoneID, func(zoneID string) (err error) {
+		records, err = c.DNSClient.GetRecordsContext(ctx, zoneID)
+		return err
+	})
+	return records, err
+}
+
+// GetRecordsPageContext lists a page of the records of a zone, looking it up
+// again if its cached ID is stale
+func (c *cachedClient) GetRecordsPageContext(ctx context.Context, zoneID string, page, perPage int) (*api.RecordsResponse, error) {
+	var resp *api.RecordsResponse
+	err := c.withZoneID(ctx, zoneID, func(zoneID string) (err error) {
+		resp, err = c.DNSClient.GetRecordsPageContext(ctx, zoneID, page, perPage)
+		return err
+	})
+	return resp, err
+}
+
+// CreateRecordContext creates a record, looking its zone up again if the
+// cached zone ID is stale
+func (c *cachedClient) CreateRecordContext(ctx context.Context, record api.Record) (*api.Record, error) {
+	var created *api.Record
+	err := c.withZoneID(ctx, record.ZoneID, func(zoneID string) (err error) {
+		record.ZoneID = zoneID
+		created, err = c.DNSClient.CreateRecordContext(ctx, record)
+		return err
+	})
+	return created, err
+}
+
+// UpdateRecordContext updates a record, looking its zone up again if the
+// cached zone ID is stale
+func (c *cachedClient) UpdateRecordContext(ctx context.Context, record api.Record) (*api.Record, error) {
+	var updated *api.Record
+	err := c.withZoneID(ctx, record.ZoneID, func(zoneID string) (err error) {
+		record.ZoneID = zoneID
+		updated, err = c.DNSClient.UpdateRecordContext(ctx, record)
+		return err
+	})
+	return updated, err
+}
+
+// GetPrimaryServersContext lists the primary servers of a zone, looking it up
+// again if its cached ID is stale
+func (c *cachedClient) GetPrimaryServersContext(ctx context.Context, zoneID string) ([]api.PrimaryServer, error) {
+	var servers []api.PrimaryServer
+	err := c.withZoneID(ctx, zoneID, func(zoneID string) (err error) {
+		servers, err = c.DNSClient.GetPrimaryServersContext(ctx, zoneID)
+		return err
+	})
+	return servers, err
+}
+
+// CreatePrimaryServerContext adds a primary server, looking its zone up again
+// if the cached zone ID is stale
+func (c *cachedClient) CreatePrimaryServerContext(ctx context.Context, server api.PrimaryServer) (*api.PrimaryServer, error) {
+	var created *api.PrimaryServer
+	err := c.withZoneID(ctx, server.ZoneID, func(zoneID string) (err error) {
+		server.ZoneID = zoneID
+		created, err = c.DNSClient.CreatePrimaryServerContext(ctx, server)
+		return err
+	})
+	return created, err
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/shotgundd/hetznerdns/pkg/api"
+	"github.com/shotgundd/hetznerdns/pkg/api/apitest"
+)
+
+func TestZoneCache(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	cache, err := newZoneCache("https://dns.example/api/v1", "token-a")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	if err := cache.store(api.Zone{ID: "zone1", Name: "Example.com."}, api.Zone{ID: "zone2", Name: "example.org"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if id, ok := cache.lookup("example.com"); !ok || id != "zone1" {
+		t.Errorf("Expected zone1 from the cache, got %q (%v)", id, ok)
+	}
+
+	// Another token does not see the cached IDs
+	other, _ := newZoneCache("https://dns.example/api/v1", "token-b")
+	if _, ok := other.lookup("example.com"); ok {
+		t.Error("Expected the cache to be scoped to the token")
+	}
+
+	if err := cache.forget("zone1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := cache.lookup("example.com"); ok {
+		t.Error("Expected the deleted zone to be removed from the cache")
+	}
+
+	now = now.Add(zoneCacheTTL + time.Second)
+	if _, ok := cache.lookup("example.org"); ok {
+		t.Error("Expected the cached ID to expire")
+	}
+}
+
+func TestCachedClientStaleZoneID(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	server := apitest.NewServer()
+	defer server.Close()
+	zone := server.AddZone(api.Zone{Name: "example.com"})
+	server.AddRecord(api.Record{ZoneID: zone.ID, Name: "www", Type: "A", Value: "192.0.2.1"})
+
+	// The zone was deleted and created again since its ID was cached
+	cache, err := newZoneCache(server.URL, server.Token)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	cache.store(api.Zone{ID: "deleted-zone", Name: "example.com"})
+	client := &cachedClient{DNSClient: server.Client(), cache: cache, readCache: true}
+
+	ctx := context.Background()
+	zoneID, err := client.GetZoneIDByNameContext(ctx, "example.com")
+	if err != nil || zoneID != "deleted-zone" {
+		t.Fatalf("Expected the cached ID, got %q (%v)", zoneID, err)
+	}
+	records, err := client.GetRecordsContext(ctx, zoneID)
+	if err != nil || len(records) != 1 {
+		t.Fatalf("Expected the records of the zone looked up again, got %+v (%v)", records, err)
+	}
+	if id, ok := cache.lookup("example.com"); !ok || id != zone.ID {
+		t.Errorf("Expected the cache to hold %s, got %q (%v)", zone.ID, id, ok)
+	}
+
+	// Later requests of the same run use the new ID right away
+	requests := len(server.Requests())
+	if _, err := client.CreateRecordContext(ctx, api.Record{ZoneID: zoneID, Name: "mail", Type: "A", Value: "192.0.2.2"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := len(server.Requests()) - requests; n != 1 {
+		t.Errorf("Expected a single request, got %d", n)
+	}
+
+	// IDs given by the user are not looked up again
+	if _, err := client.GetRecordsContext(ctx, "unknown-zone"); err == nil {
+		t.Error("Expected an error for an unknown zone ID")
+	}
+}
@@ -0,0 +1,666 @@
+// Package apitest provides an in-memory fake of the Hetzner DNS API for
+// tests of code built on the api package.
+//
+// The fake keeps zones and records in memory, generates IDs, validates
+// requests like the real API, paginates listings and can inject errors and
+// simulate rate limiting:
+//
+//	server := apitest.NewServer()
+//	defer server.Close()
+//
+//	zone := server.AddZone(api.Zone{Name: "example.com"})
+//	client := server.Client()
+//	records, err := client.GetRecords(zone.ID)
+//
+// Unlike the real API, timestamps are encoded in RFC 3339 format and new
+// zones start without SOA and NS records.
+package apitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shotgundd/hetznerdns/pkg/api"
+)
+
+// DefaultToken is the API token the fake accepts unless Token is changed
+const DefaultToken = "test-token"
+
+// DefaultPerPage is the page size of listings that do not ask for one
+const DefaultPerPage = 100
+
+// DefaultTTL is the TTL of zones created without one
+const DefaultTTL = 86400
+
+// recordTypes are the record types the fake accepts
+var recordTypes = map[string]bool{
+	"A": true, "AAAA": true, "NS": true, "MX": true, "CNAME": true, "RP": true, "TXT": true, "SOA": true,
+	"HINFO": true, "SRV": true, "DANE": true, "TLSA": true, "DS": true, "CAA": true, "PTR": true,
+}
+
+// Server is a fake Hetzner DNS API served over HTTP. It is safe for
+// concurrent use.
+type Server struct {
+	*httptest.Server
+
+	// Token is the API token requests have to send. It may only be changed
+	// before the first request.
+	Token string
+
+	mu             sync.Mutex
+	zones          map[string]*api.Zone
+	records        map[string]*api.Record
+	nextID         int
+	failures       []*failure
+	requests       []string
+	rateLimit      int
+	rateWindow     time.Duration
+	windowStart    time.Time
+	windowRequests int
+}
+
+// failure is an error injected with FailNext
+type failure struct {
+	method    string
+	path      string
+	status    int
+	remaining int
+}
+
+// NewServer starts a fake API without any zones. Call Close when done.
+func NewServer() *Server {
+	s := &Server{
+		Token:   DefaultToken,
+		zones:   make(map[string]*api.Zone),
+		records: make(map[string]*api.Record),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns an API client talking to the fake with the accepted token.
+// The options are applied after the ones pointing the client at the fake.
+func (s *Server) Client(opts ...api.Option) *api.Client {
+	opts = append([]api.Option{api.WithBaseURL(s.URL), api.WithHTTPClient(s.Server.Client())}, opts...)
+	return api.NewClient(s.Token, opts...)
+}
+
+// AddZone stores a zone without validating it and returns it as stored. An
+// ID and a TTL are filled in if missing.
+func (s *Server) AddZone(zone api.Zone) api.Zone {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.addZone(zone)
+}
+
+// AddRecord stores a record without validating it and returns it as stored.
+// An ID is filled in if missing.
+func (s *Server) AddRecord(record api.Record) api.Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.addRecord(record)
+}
+
+// Zones returns all stored zones sorted by name
+func (s *Server) Zones() []api.Zone {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	zones := make([]api.Zone, 0, len(s.zones))
+	for _, zone := range s.sortedZones() {
+		zones = append(zones, *zone)
+	}
+	return zones
+}
+
+// Records returns the stored records of a zone, or of all zones if zoneID is
+// empty, sorted by zone, name, type and value
+func (s *Server) Records(zoneID string) []api.Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var records []api.Record
+	for _, record := range s.sortedRecords(zoneID) {
+		records = append(records, *record)
+	}
+	return records
+}
+
+// FailNext makes the next n requests matching method and path fail with the
+// given status code and an error body. An empty method or path matches every
+// request; path is compared without the query string.
+func (s *Server) FailNext(method, path string, status, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &failure{method: method, path: path, status: status, remaining: n})
+}
+
+// SetRateLimit allows limit requests per window. Further requests in the
+// same window are answered with 429 Too Many Requests and a Retry-After
+// header. Every response reports the quota in RateLimit-* headers. A limit
+// of zero disables rate limiting.
+func (s *Server) SetRateLimit(limit int, window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimit = limit
+	s.rateWindow = window
+	s.windowStart = time.Time{}
+	s.windowRequests = 0
+}
+
+// Requests returns the requests received so far as "METHOD /path?query"
+func (s *Server) Requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.requests...)
+}
+
+// serveHTTP handles a request to the fake API
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, r.Method+" "+r.URL.RequestURI())
+	w.Header().Set("Content-Type", "application/json")
+
+	if !s.allowRequest(w) {
+		writeError(w, http.StatusTooManyRequests, "rate limit exceeded")
+		return
+	}
+	if r.Header.Get("Auth-API-Token") != s.Token {
+		writeError(w, http.StatusUnauthorized, "invalid API token")
+		return
+	}
+	if status, ok := s.injectedFailure(r); ok {
+		writeError(w, status, "injected failure")
+		return
+	}
+
+	segments := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case len(segments) == 1 && segments[0] == "zones":
+		switch r.Method {
+		case http.MethodGet:
+			s.listZones(w, r)
+		case http.MethodPost:
+			s.createZone(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	case len(segments) == 2 && segments[0] == "zones":
+		s.handleZone(w, r, segments[1])
+	case len(segments) == 1 && segments[0] == "records":
+		switch r.Method {
+		case http.MethodGet:
+			s.listRecords(w, r)
+		case http.MethodPost:
+			s.createRecord(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	case len(segments) == 2 && segments[0] == "records" && segments[1] == "bulk":
+		switch r.Method {
+		case http.MethodPost:
+			s.bulkCreateRecords(w, r)
+		case http.MethodPut:
+			s.bulkUpdateRecords(w, r)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		}
+	case len(segments) == 2 && segments[0] == "records":
+		s.handleRecord(w, r, segments[1])
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// allowRequest counts a request against the rate limit, sets the quota
+// headers and reports whether the request is within the limit
+func (s *Server) allowRequest(w http.ResponseWriter) bool {
+	if s.rateLimit <= 0 {
+		return true
+	}
+
+	now := time.Now()
+	if now.Sub(s.windowStart) >= s.rateWindow {
+		s.windowStart = now
+		s.windowRequests = 0
+	}
+	s.windowRequests++
+
+	reset := int((s.windowStart.Add(s.rateWindow).Sub(now) + time.Second - 1) / time.Second)
+	w.Header().Set("RateLimit-Limit", strconv.Itoa(s.rateLimit))
+	w.Header().Set("RateLimit-Remaining", strconv.Itoa(max(s.rateLimit-s.windowRequests, 0)))
+	w.Header().Set("RateLimit-Reset", strconv.Itoa(reset))
+
+	if s.windowRequests > s.rateLimit {
+		w.Header().Set("Retry-After", strconv.Itoa(reset))
+		return false
+	}
+	return true
+}
+
+// injectedFailure returns the status of the first injected failure matching r
+func (s *Server) injectedFailure(r *http.Request) (int, bool) {
+	for i, f := range s.failures {
+		if (f.method != "" && f.method != r.Method) || (f.path != "" && f.path != r.URL.Path) {
+			continue
+		}
+		f.remaining--
+		if f.remaining <= 0 {
+			s.failures = append(s.failures[:i], s.failures[i+1:]...)
+		}
+		return f.status, true
+	}
+	return 0, false
+}
+
+// listZones answers GET /zones, optionally filtered by the name parameter
+func (s *Server) listZones(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+
+	var zones []api.Zone
+	for _, zone := range s.sortedZones() {
+		if name == "" || strings.EqualFold(zone.Name, name) {
+			zones = append(zones, *zone)
+		}
+	}
+
+	start, end, pagination, ok := paginate(w, r, len(zones))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, api.ZonesResponse{Zones: append([]api.Zone{}, zones[start:end]...), Meta: api.Meta{Pagination: pagination}})
+}
+
+// createZone answers POST /zones
+func (s *Server) createZone(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Name string `json:"name"`
+		TTL  int    `json:"ttl"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+	if msg := s.validateZoneName(body.Name, ""); msg != "" {
+		writeError(w, http.StatusUnprocessableEntity, msg)
+		return
+	}
+
+	zone := s.addZone(api.Zone{Name: body.Name, TTL: body.TTL})
+	writeJSON(w, http.StatusOK, api.ZoneResponse{Zone: *zone})
+}
+
+// handleZone answers requests for a single zone
+func (s *Server) handleZone(w http.ResponseWriter, r *http.Request, id string) {
+	zone, ok := s.zones[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "zone not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, api.ZoneResponse{Zone: *zone})
+	case http.MethodPut:
+		var body struct {
+			Name string `json:"name"`
+			TTL  int    `json:"ttl"`
+		}
+		if !readJSON(w, r, &body) {
+			return
+		}
+		if msg := s.validateZoneName(body.Name, id); msg != "" {
+			writeError(w, http.StatusUnprocessableEntity, msg)
+			return
+		}
+		zone.Name = body.Name
+		if body.TTL > 0 {
+			zone.TTL = body.TTL
+		}
+		zone.Modified = now()
+		writeJSON(w, http.StatusOK, api.ZoneResponse{Zone: *zone})
+	case http.MethodDelete:
+		delete(s.zones, id)
+		for recordID, record := range s.records {
+			if record.ZoneID == id {
+				delete(s.records, recordID)
+			}
+		}
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// listRecords answers GET /records, optionally filtered by the zone_id parameter
+func (s *Server) listRecords(w http.ResponseWriter, r *http.Request) {
+	zoneID := r.URL.Query().Get("zone_id")
+	if _, ok := s.zones[zoneID]; zoneID != "" && !ok {
+		writeError(w, http.StatusNotFound, "zone not found")
+		return
+	}
+
+	var records []api.Record
+	for _, record := range s.sortedRecords(zoneID) {
+		records = append(records, *record)
+	}
+
+	start, end, pagination, ok := paginate(w, r, len(records))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, api.RecordsResponse{Records: append([]api.Record{}, records[start:end]...), Meta: api.Meta{Pagination: pagination}})
+}
+
+// createRecord answers POST /records
+func (s *Server) createRecord(w http.ResponseWriter, r *http.Request) {
+	var record api.Record
+	if !readJSON(w, r, &record) {
+		return
+	}
+	if msg := s.validateRecord(record); msg != "" {
+		writeError(w, http.StatusUnprocessableEntity, msg)
+		return
+	}
+
+	record.ID = ""
+	writeJSON(w, http.StatusOK, api.RecordResponse{Record: *s.addRecord(record)})
+}
+
+// handleRecord answers requests for a single record
+func (s *Server) handleRecord(w http.ResponseWriter, r *http.Request, id string) {
+	record, ok := s.records[id]
+	if !ok {
+		writeError(w, http.StatusNotFound, "record not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, api.RecordResponse{Record: *record})
+	case http.MethodPut:
+		var update api.Record
+		if !readJSON(w, r, &update) {
+			return
+		}
+		if msg := s.validateRecord(update); msg != "" {
+			writeError(w, http.StatusUnprocessableEntity, msg)
+			return
+		}
+		s.updateRecord(record, update)
+		writeJSON(w, http.StatusOK, api.RecordResponse{Record: *record})
+	case http.MethodDelete:
+		s.deleteRecord(id)
+		w.WriteHeader(http.StatusOK)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// bulkCreateRecords answers POST /records/bulk
+func (s *Server) bulkCreateRecords(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Records []api.Record `json:"records"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+
+	result := api.BulkCreateResult{Records: []api.Record{}, ValidRecords: []api.Record{}, InvalidRecords: []api.Record{}}
+	for _, record := range body.Records {
+		if s.validateRecord(record) != "" {
+			result.InvalidRecords = append(result.InvalidRecords, record)
+			continue
+		}
+		result.ValidRecords = append(result.ValidRecords, record)
+		record.ID = ""
+		result.Records = append(result.Records, *s.addRecord(record))
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// bulkUpdateRecords answers PUT /records/bulk
+func (s *Server) bulkUpdateRecords(w http.ResponseWriter, r *http.Request) {
+	var body struct {
+		Records []api.Record `json:"records"`
+	}
+	if !readJSON(w, r, &body) {
+		return
+	}
+
+	result := api.BulkUpdateResult{Records: []api.Record{}, FailedRecords: []api.Record{}}
+	for _, update := range body.Records {
+		record, ok := s.records[update.ID]
+		if !ok || s.validateRecord(update) != "" {
+			result.FailedRecords = append(result.FailedRecords, update)
+			continue
+		}
+		s.updateRecord(record, update)
+		result.Records = append(result.Records, *record)
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// validateZoneName returns why name cannot be used for the zone with the
+// given ID, or an empty string if it can
+func (s *Server) validateZoneName(name, id string) string {
+	if name == "" {
+		return "zone name is required"
+	}
+	if !strings.Contains(strings.TrimSuffix(name, "."), ".") {
+		return fmt.Sprintf("invalid zone name %q", name)
+	}
+	for _, zone := range s.zones {
+		if zone.ID != id && strings.EqualFold(zone.Name, name) {
+			return fmt.Sprintf("zone %q already exists", name)
+		}
+	}
+	return ""
+}
+
+// validateRecord returns why the API would reject record, or an empty
+// string if it is valid
+func (s *Server) validateRecord(record api.Record) string {
+	if _, ok := s.zones[record.ZoneID]; !ok {
+		return fmt.Sprintf("zone %q not found", record.ZoneID)
+	}
+	if record.Name == "" {
+		return "record name is required"
+	}
+	if !recordTypes[record.Type] {
+		return fmt.Sprintf("invalid record type %q", record.Type)
+	}
+	if record.Value == "" {
+		return "record value is required"
+	}
+	if record.TTL < 0 {
+		return "ttl must not be negative"
+	}
+
+	ip := net.ParseIP(record.Value)
+	switch {
+	case record.Type == "A" && (ip == nil || ip.To4() == nil):
+		return fmt.Sprintf("invalid IPv4 address %q", record.Value)
+	case record.Type == "AAAA" && (ip == nil || ip.To4() != nil):
+		return fmt.Sprintf("invalid IPv6 address %q", record.Value)
+	}
+	return ""
+}
+
+// addZone stores zone, filling in missing fields
+func (s *Server) addZone(zone api.Zone) *api.Zone {
+	if zone.ID == "" {
+		zone.ID = s.newID("zn")
+	}
+	if zone.TTL == 0 {
+		zone.TTL = DefaultTTL
+	}
+	if zone.Status == "" {
+		zone.Status = api.ZoneStatusVerified
+	}
+	if zone.NS == nil {
+		zone.NS = []string{"hydrogen.ns.hetzner.com", "oxygen.ns.hetzner.com", "helium.ns.hetzner.de"}
+	}
+	if zone.Created.IsZero() {
+		zone.Created = now()
+		zone.Modified = zone.Created
+	}
+	zone.RecordsCount = 0
+	for _, record := range s.records {
+		if record.ZoneID == zone.ID {
+			zone.RecordsCount++
+		}
+	}
+
+	s.zones[zone.ID] = &zone
+	return &zone
+}
+
+// addRecord stores record, filling in missing fields
+func (s *Server) addRecord(record api.Record) *api.Record {
+	if record.ID == "" {
+		record.ID = s.newID("rc")
+	}
+	if record.Created.IsZero() {
+		record.Created = now()
+		record.Modified = record.Created
+	}
+
+	if old, ok := s.records[record.ID]; ok {
+		s.countRecord(old.ZoneID, -1)
+	}
+	s.records[record.ID] = &record
+	s.countRecord(record.ZoneID, 1)
+	return &record
+}
+
+// updateRecord applies the fields of update to record
+func (s *Server) updateRecord(record *api.Record, update api.Record) {
+	s.countRecord(record.ZoneID, -1)
+	record.ZoneID = update.ZoneID
+	record.Name = update.Name
+	record.Type = update.Type
+	record.Value = update.Value
+	record.TTL = update.TTL
+	record.Modified = now()
+	s.countRecord(record.ZoneID, 1)
+}
+
+// deleteRecord removes the record with the given ID
+func (s *Server) deleteRecord(id string) {
+	if record, ok := s.records[id]; ok {
+		s.countRecord(record.ZoneID, -1)
+		delete(s.records, id)
+	}
+}
+
+// countRecord adjusts the records count of a zone
+func (s *Server) countRecord(zoneID string, delta int) {
+	if zone, ok := s.zones[zoneID]; ok {
+		zone.RecordsCount += delta
+	}
+}
+
+// newID generates an ID that is unique within the server
+func (s *Server) newID(prefix string) string {
+	s.nextID++
+	return fmt.Sprintf("%s%06d", prefix, s.nextID)
+}
+
+// sortedZones returns the stored zones sorted by name
+func (s *Server) sortedZones() []*api.Zone {
+	zones := make([]*api.Zone, 0, len(s.zones))
+	for _, zone := range s.zones {
+		zones = append(zones, zone)
+	}
+	sort.Slice(zones, func(i, j int) bool { return zones[i].Name < zones[j].Name })
+	return zones
+}
+
+// sortedRecords returns the stored records of a zone, or of all zones if
+// zoneID is empty, sorted by zone, name, type and value
+func (s *Server) sortedRecords(zoneID string) []*api.Record {
+	var records []*api.Record
+	for _, record := range s.records {
+		if zoneID == "" || record.ZoneID == zoneID {
+			records = append(records, record)
+		}
+	}
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.ZoneID != b.ZoneID {
+			return a.ZoneID < b.ZoneID
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Value < b.Value
+	})
+	return records
+}
+
+// paginate reads the page and per_page parameters of a listing of total
+// entries. It returns the bounds of the requested page and the pagination
+// metadata, or writes an error and returns false.
+func paginate(w http.ResponseWriter, r *http.Request, total int) (start, end int, pagination api.Pagination, ok bool) {
+	page, perPage := 1, DefaultPerPage
+	for name, target := range map[string]*int{"page": &page, "per_page": &perPage} {
+		if value := r.URL.Query().Get(name); value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid %s %q", name, value))
+				return 0, 0, api.Pagination{}, false
+			}
+			*target = n
+		}
+	}
+
+	pagination = api.Pagination{
+		Page:         page,
+		PerPage:      perPage,
+		LastPage:     max((total+perPage-1)/perPage, 1),
+		TotalEntries: total,
+	}
+	start = min((page-1)*perPage, total)
+	end = min(start+perPage, total)
+	return start, end, pagination, true
+}
+
+// readJSON decodes the request body into v, or writes an error and returns false
+func readJSON(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err))
+		return false
+	}
+	return true
+}
+
+// writeJSON writes v as the response body
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes an error response in the format used by the API
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]interface{}{
+		"error": map[string]interface{}{"message": message, "code": status},
+	})
+}
+
+// now returns the current time as stored in timestamps
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Millisecond)
+}
@@ -0,0 +1,169 @@
+package apitest
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/shotgundd/hetznerdns/pkg/api"
+)
+
+func TestZoneLifecycle(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+
+	zone, err := client.CreateZone("example.com", 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if zone.ID == "" || zone.TTL != DefaultTTL || zone.Created.IsZero() {
+		t.Errorf("Expected ID, default TTL and creation time to be set, got %+v", zone)
+	}
+
+	if _, err := client.CreateZone("example.com", 0); !errors.Is(err, api.ErrValidation) {
+		t.Errorf("Expected a validation error for a duplicate zone, got %v", err)
+	}
+
+	id, err := client.GetZoneIDByName("example.com")
+	if err != nil || id != zone.ID {
+		t.Errorf("Expected zone ID %s, got %s (%v)", zone.ID, id, err)
+	}
+
+	zone.TTL = 3600
+	if updated, err := client.UpdateZone(*zone); err != nil || updated.TTL != 3600 {
+		t.Errorf("Expected TTL 3600 after update, got %+v (%v)", updated, err)
+	}
+
+	if err := client.DeleteZone(zone.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := client.GetZone(zone.ID); !errors.Is(err, api.ErrNotFound) {
+		t.Errorf("Expected not found after delete, got %v", err)
+	}
+}
+
+func TestRecordLifecycle(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	zone := server.AddZone(api.Zone{Name: "example.com"})
+
+	record, err := client.CreateRecord(api.Record{ZoneID: zone.ID, Type: "A", Name: "www", Value: "192.0.2.1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	record.Value = "192.0.2.2"
+	if _, err := client.UpdateRecord(*record); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	got, err := client.GetRecord(record.ID)
+	if err != nil || got.Value != "192.0.2.2" {
+		t.Errorf("Expected updated value, got %+v (%v)", got, err)
+	}
+	if stored, _ := client.GetZone(zone.ID); stored.RecordsCount != 1 {
+		t.Errorf("Expected records count 1, got %d", stored.RecordsCount)
+	}
+
+	if err := client.DeleteRecord(record.ID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if records := server.Records(zone.ID); len(records) != 0 {
+		t.Errorf("Expected no records after delete, got %+v", records)
+	}
+}
+
+func TestRecordValidation(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	zone := server.AddZone(api.Zone{Name: "example.com"})
+
+	tests := []api.Record{
+		{ZoneID: zone.ID, Type: "A", Name: "www", Value: "2001:db8::1"},
+		{ZoneID: zone.ID, Type: "AAAA", Name: "www", Value: "192.0.2.1"},
+		{ZoneID: zone.ID, Type: "BOGUS", Name: "www", Value: "x"},
+		{ZoneID: zone.ID, Type: "TXT", Name: "", Value: "x"},
+		{ZoneID: "missing", Type: "TXT", Name: "www", Value: "x"},
+	}
+	for _, record := range tests {
+		if _, err := client.CreateRecord(record); !errors.Is(err, api.ErrValidation) {
+			t.Errorf("Expected a validation error for %+v, got %v", record, err)
+		}
+	}
+
+	result, err := client.CreateRecords(append(tests, api.Record{ZoneID: zone.ID, Type: "A", Name: "@", Value: "192.0.2.1"}))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Records) != 1 || len(result.InvalidRecords) != len(tests) {
+		t.Errorf("Expected 1 created and %d invalid records, got %+v", len(tests), result)
+	}
+}
+
+func TestPagination(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client()
+	zone := server.AddZone(api.Zone{Name: "example.com"})
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		server.AddRecord(api.Record{ZoneID: zone.ID, Type: "A", Name: name, Value: "192.0.2.1"})
+	}
+
+	page, err := client.GetRecordsPage(zone.ID, 2, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(page.Records) != 2 || page.Records[0].Name != "c" || page.Meta.Pagination.LastPage != 3 || page.Meta.Pagination.TotalEntries != 5 {
+		t.Errorf("Unexpected page %+v", page)
+	}
+
+	records, err := client.GetRecords(zone.ID)
+	if err != nil || len(records) != 5 {
+		t.Errorf("Expected all 5 records, got %d (%v)", len(records), err)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	client := api.NewClient("wrong-token", api.WithBaseURL(server.URL))
+	if _, err := client.GetZones(); !errors.Is(err, api.ErrUnauthorized) {
+		t.Errorf("Expected an unauthorized error, got %v", err)
+	}
+}
+
+func TestFailNext(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	client := server.Client(api.WithRetryPolicy(api.RetryPolicy{MaxAttempts: 1}))
+
+	server.FailNext(http.MethodGet, "/zones", http.StatusInternalServerError, 1)
+	if _, err := client.GetZones(); !errors.Is(err, api.ErrServer) {
+		t.Errorf("Expected an injected server error, got %v", err)
+	}
+	if _, err := client.GetZones(); err != nil {
+		t.Errorf("Expected the failure to be used up, got %v", err)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+	server.SetRateLimit(1, time.Minute)
+	client := server.Client(api.WithRetryPolicy(api.RetryPolicy{MaxAttempts: 1}))
+
+	if _, err := client.GetZones(); err != nil {
+		t.Fatalf("Expected the first request to pass, got %v", err)
+	}
+	_, err := client.GetZones()
+	if !errors.Is(err, api.ErrRateLimited) {
+		t.Fatalf("Expected a rate limit error, got %v", err)
+	}
+	if len(server.Requests()) != 2 {
+		t.Errorf("Expected 2 requests, got %v", server.Requests())
+	}
+}
@@ -2,79 +2,380 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 const (
-	baseURL = "https://dns.hetzner.com/api/v1"
+	// DefaultBaseURL is the Hetzner DNS API endpoint used unless WithBaseURL is given
+	DefaultBaseURL = "https://dns.hetzner.com/api/v1"
+
+	// DefaultTimeout is the HTTP timeout used unless WithTimeout or WithHTTPClient is given
+	DefaultTimeout = 10 * time.Second
+
+	// DefaultUserAgent is the User-Agent header sent unless WithUserAgent is given
+	DefaultUserAgent = "hetznerdns-go"
+
+	// DefaultPerPage is the page size used by GetZones and GetRecords unless
+	// WithPerPage is given
+	DefaultPerPage = 100
 )
 
 // Client represents a Hetzner DNS API client
 type Client struct {
-	apiToken   string
-	httpClient *http.Client
+	apiToken    string
+	baseURL     string
+	userAgent   string
+	perPage     int
+	retryPolicy RetryPolicy
+	rateLimiter *RateLimiter
+	httpClient  *http.Client
+
+	rateLimitMu sync.Mutex
+	rateLimit   RateLimitStatus
+}
+
+// clientOptions collects the settings applied by Option values before the
+// Client is assembled, so options can be given in any order
+type clientOptions struct {
+	baseURL     string
+	userAgent   string
+	httpClient  *http.Client
+	transport   http.RoundTripper
+	timeout     time.Duration
+	perPage     int
+	retryPolicy RetryPolicy
+	rateLimiter *RateLimiter
+	debugLog    io.Writer
+	har         *HARRecorder
+}
+
+// Option configures a Client created by NewClient
+type Option func(*clientOptions)
+
+// WithBaseURL points the client at a different API endpoint, e.g. a proxy or test server
+func WithBaseURL(baseURL string) Option {
+	return func(o *clientOptions) {
+		o.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithHTTPClient makes the client send requests through the given http.Client.
+// The http.Client is copied, so later options never modify the caller's value.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *clientOptions) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithTransport sets the RoundTripper used to send requests
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *clientOptions) {
+		o.transport = transport
+	}
+}
+
+// WithTimeout sets the overall timeout of a single HTTP request
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *clientOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent with every request
+func WithUserAgent(userAgent string) Option {
+	return func(o *clientOptions) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithPerPage sets the page size GetZones and GetRecords request while
+// walking through all pages of a listing
+func WithPerPage(perPage int) Option {
+	return func(o *clientOptions) {
+		o.perPage = perPage
+	}
 }
 
 // NewClient creates a new Hetzner DNS API client
-func NewClient(apiToken string) *Client {
+func NewClient(apiToken string, opts ...Option) *Client {
+	o := clientOptions{
+		baseURL:   DefaultBaseURL,
+		userAgent: DefaultUserAgent,
+		perPage:   DefaultPerPage,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	httpClient := &http.Client{Timeout: DefaultTimeout}
+	if o.httpClient != nil {
+		copied := *o.httpClient
+		httpClient = &copied
+	}
+	if o.transport != nil {
+		httpClient.Transport = o.transport
+	}
+	if o.timeout > 0 {
+		httpClient.Timeout = o.timeout
+	}
+	if o.debugLog != nil || o.har != nil {
+		base := httpClient.Transport
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		httpClient.Transport = &debugTransport{base: base, log: o.debugLog, har: o.har}
+	}
+
 	return &Client{
-		apiToken: apiToken,
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		apiToken:    apiToken,
+		baseURL:     o.baseURL,
+		userAgent:   o.userAgent,
+		perPage:     o.perPage,
+		retryPolicy: o.retryPolicy,
+		rateLimiter: o.rateLimiter,
+		httpClient:  httpClient,
 	}
 }
 
+// newRequest builds a request for the given API path with authentication and
+// the common headers set
+func (c *Client) newRequest(ctx context.Context, method, path string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Auth-API-Token", c.apiToken)
+	req.Header.Set("Content-Type", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	return req, nil
+}
+
+// send sends req and returns the response body. Responses with a status code
+// outside of 2xx yield an *APIError.
+func (c *Client) send(req *http.Request) ([]byte, error) {
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, newAPIError(resp, body)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// doJSON sends a request with payload encoded as JSON, unless it is nil, and
+// decodes the response body into out, unless it is nil
+func (c *Client) doJSON(ctx context.Context, method, path string, payload, out interface{}) error {
+	var body io.Reader
+	if payload != nil {
+		payloadJSON, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(payloadJSON)
+	}
+
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+
+	respBody, err := c.send(req)
+	if err != nil {
+		return err
+	}
+
+	if out == nil {
+		return nil
+	}
+	return decodeJSON(respBody, out)
+}
+
+// Zone status values reported by the API
+const (
+	ZoneStatusVerified = "verified"
+	ZoneStatusFailed   = "failed"
+	ZoneStatusPending  = "pending"
+)
+
 // Zone represents a DNS zone
 type Zone struct {
 	ID           string `json:"id"`
 	Name         string `json:"name"`
 	TTL          int    `json:"ttl"`
 	RecordsCount int    `json:"records_count"`
-	// Other fields omitted for simplicity and to avoid unmarshaling issues
+	// Status is one of ZoneStatusVerified, ZoneStatusFailed or ZoneStatusPending
+	Status string `json:"status,omitempty"`
+	// NS lists the Hetzner name servers assigned to the zone
+	NS       []string  `json:"ns,omitempty"`
+	Created  time.Time `json:"created,omitzero"`
+	Modified time.Time `json:"modified,omitzero"`
+	// Verified is when the delegation to the assigned name servers was verified
+	Verified        time.Time        `json:"verified,omitzero"`
+	IsSecondaryDNS  bool             `json:"is_secondary_dns"`
+	TxtVerification *TxtVerification `json:"txt_verification,omitempty"`
+	// LegacyDNSHost and LegacyNS describe the name servers in use before the zone moved to Hetzner
+	LegacyDNSHost string   `json:"legacy_dns_host,omitempty"`
+	LegacyNS      []string `json:"legacy_ns,omitempty"`
+	Project       string   `json:"project,omitempty"`
+	Owner         string   `json:"owner,omitempty"`
+	Permission    string   `json:"permission,omitempty"`
+	Registrar     string   `json:"registrar,omitempty"`
+	Paused        bool     `json:"paused,omitempty"`
+	// Extra holds the fields of the API response this version does not know
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// MarshalJSON implements json.Marshaler, including the fields kept in Extra
+func (z Zone) MarshalJSON() ([]byte, error) {
+	type plain Zone
+	return marshalWithExtra(plain(z), z.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting the timestamp formats
+// of the API and keeping unknown fields in Extra
+func (z *Zone) UnmarshalJSON(data []byte) error {
+	type plain Zone
+	aux := struct {
+		*plain
+		Created  string `json:"created"`
+		Modified string `json:"modified"`
+		Verified string `json:"verified"`
+	}{plain: (*plain)(z)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var err error
+	if z.Created, err = decodeTimestamp("created", aux.Created); err != nil {
+		return err
+	}
+	if z.Modified, err = decodeTimestamp("modified", aux.Modified); err != nil {
+		return err
+	}
+	if z.Verified, err = decodeTimestamp("verified", aux.Verified); err != nil {
+		return err
+	}
+	z.Extra, err = unknownFields(data, reflect.TypeOf(plain{}))
+	return err
+}
+
+// TxtVerification holds the TXT record used to verify ownership of a zone
+type TxtVerification struct {
+	Name  string `json:"name"`
+	Token string `json:"token"`
 }
 
 // Record represents a DNS record
 type Record struct {
-	ID       string `json:"id,omitempty"`
-	Type     string `json:"type"`
-	Name     string `json:"name"`
-	Value    string `json:"value"`
-	TTL      int    `json:"ttl,omitempty"`
-	ZoneID   string `json:"zone_id"`
-	Created  string `json:"created,omitempty"`
-	Modified string `json:"modified,omitempty"`
+	ID       string    `json:"id,omitempty"`
+	Type     string    `json:"type"`
+	Name     string    `json:"name"`
+	Value    string    `json:"value"`
+	TTL      int       `json:"ttl,omitempty"`
+	ZoneID   string    `json:"zone_id"`
+	Created  time.Time `json:"created,omitzero"`
+	Modified time.Time `json:"modified,omitzero"`
+	// Extra holds the fields of the API response this version does not know
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// MarshalJSON implements json.Marshaler, including the fields kept in Extra
+func (r Record) MarshalJSON() ([]byte, error) {
+	type plain Record
+	return marshalWithExtra(plain(r), r.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting the timestamp formats
+// of the API and keeping unknown fields in Extra
+func (r *Record) UnmarshalJSON(data []byte) error {
+	type plain Record
+	aux := struct {
+		*plain
+		Created  string `json:"created"`
+		Modified string `json:"modified"`
+	}{plain: (*plain)(r)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var err error
+	if r.Created, err = decodeTimestamp("created", aux.Created); err != nil {
+		return err
+	}
+	if r.Modified, err = decodeTimestamp("modified", aux.Modified); err != nil {
+		return err
+	}
+	r.Extra, err = unknownFields(data, reflect.TypeOf(plain{}))
+	return err
+}
+
+// recordRequest is the request body for creating and updating records
+type recordRequest struct {
+	ID     string `json:"id,omitempty"`
+	Type   string `json:"type"`
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	TTL    int    `json:"ttl,omitempty"`
+	ZoneID string `json:"zone_id"`
+}
+
+// newRecordRequest builds the request body for creating or updating record
+func newRecordRequest(record Record) recordRequest {
+	return recordRequest{
+		ID:     record.ID,
+		Type:   record.Type,
+		Name:   record.Name,
+		Value:  record.Value,
+		TTL:    record.TTL,
+		ZoneID: record.ZoneID,
+	}
+}
+
+// Pagination describes which page of a listing a response contains
+type Pagination struct {
+	Page         int `json:"page"`
+	PerPage      int `json:"per_page"`
+	LastPage     int `json:"last_page"`
+	TotalEntries int `json:"total_entries"`
+}
+
+// Meta holds the metadata attached to listing responses
+type Meta struct {
+	Pagination Pagination `json:"pagination"`
 }
 
 // ZonesResponse represents the response from the zones endpoint
 type ZonesResponse struct {
 	Zones []Zone `json:"zones"`
-	Meta  struct {
-		Pagination struct {
-			Page         int `json:"page"`
-			PerPage      int `json:"per_page"`
-			LastPage     int `json:"last_page"`
-			TotalEntries int `json:"total_entries"`
-		} `json:"pagination"`
-	} `json:"meta"`
+	Meta  Meta   `json:"meta"`
 }
 
 // RecordsResponse represents the response from the records endpoint
 type RecordsResponse struct {
 	Records []Record `json:"records"`
-	Meta    struct {
-		Pagination struct {
-			Page         int `json:"page"`
-			PerPage      int `json:"per_page"`
-			LastPage     int `json:"last_page"`
-			TotalEntries int `json:"total_entries"`
-		} `json:"pagination"`
-	} `json:"meta"`
+	Meta    Meta     `json:"meta"`
 }
 
 // RecordResponse represents the response when creating or getting a single record
@@ -87,341 +388,434 @@ type ZoneResponse struct {
 	Zone Zone `json:"zone"`
 }
 
-// GetZones retrieves all DNS zones
-func (c *Client) GetZones() ([]Zone, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/zones", baseURL), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Auth-API-Token", c.apiToken)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error: %s, status code: %d", string(body), resp.StatusCode)
-	}
+// zoneEnvelope and recordEnvelope decode responses holding a single object,
+// telling a missing object apart from an empty one
+type zoneEnvelope struct {
+	Zone *Zone `json:"zone"`
+}
 
-	// Use a map to avoid unmarshaling issues with unexpected fields
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
-	}
+type recordEnvelope struct {
+	Record *Record `json:"record"`
+}
 
-	// Extract zones from the response
-	zonesData, ok := result["zones"].([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("unexpected response format: zones field not found or not an array")
-	}
+// GetZones retrieves all DNS zones, following pagination until the last page
+func (c *Client) GetZones() ([]Zone, error) {
+	return c.GetZonesContext(context.Background())
+}
 
+// GetZonesContext is like GetZones but uses ctx for all requests
+func (c *Client) GetZonesContext(ctx context.Context) ([]Zone, error) {
 	var zones []Zone
-	for _, zoneData := range zonesData {
-		zoneMap, ok := zoneData.(map[string]interface{})
-		if !ok {
-			continue
+	for page := 1; ; page++ {
+		resp, err := c.GetZonesPageContext(ctx, page, c.perPage)
+		if err != nil {
+			return nil, err
 		}
 
-		zone := Zone{}
-
-		// Extract ID
-		if id, ok := zoneMap["id"].(string); ok {
-			zone.ID = id
+		zones = append(zones, resp.Zones...)
+		if isLastPage(resp.Meta.Pagination, page, len(resp.Zones)) {
+			return zones, nil
 		}
+	}
+}
 
-		// Extract Name
-		if name, ok := zoneMap["name"].(string); ok {
-			zone.Name = name
-		}
+// GetZonesPage retrieves a single page of DNS zones. A perPage of zero or
+// less uses the API default.
+func (c *Client) GetZonesPage(page, perPage int) (*ZonesResponse, error) {
+	return c.GetZonesPageContext(context.Background(), page, perPage)
+}
 
-		// Extract TTL
-		if ttl, ok := zoneMap["ttl"].(float64); ok {
-			zone.TTL = int(ttl)
-		}
+// GetZonesPageContext is like GetZonesPage but uses ctx for the request
+func (c *Client) GetZonesPageContext(ctx context.Context, page, perPage int) (*ZonesResponse, error) {
+	zonesResp := &ZonesResponse{}
+	if err := c.doJSON(ctx, "GET", "/zones"+pageQuery(nil, page, perPage), nil, zonesResp); err != nil {
+		return nil, err
+	}
+	return zonesResp, nil
+}
 
-		// Extract RecordsCount
-		if recordsCount, ok := zoneMap["records_count"].(float64); ok {
-			zone.RecordsCount = int(recordsCount)
-		}
+// GetZoneIDByName retrieves a zone ID by its name
+func (c *Client) GetZoneIDByName(name string) (string, error) {
+	return c.GetZoneIDByNameContext(context.Background(), name)
+}
 
-		zones = append(zones, zone)
+// GetZoneIDByNameContext is like GetZoneIDByName but uses ctx for all requests
+func (c *Client) GetZoneIDByNameContext(ctx context.Context, name string) (string, error) {
+	zone, err := c.GetZoneByNameContext(ctx, name)
+	if err != nil {
+		return "", err
 	}
+	return zone.ID, nil
+}
 
-	return zones, nil
+// GetZoneByName retrieves a zone by its name, ignoring case and a trailing
+// dot. The API filters the zones by name, so only the matching zone is
+// transferred.
+func (c *Client) GetZoneByName(name string) (*Zone, error) {
+	return c.GetZoneByNameContext(context.Background(), name)
 }
 
-// GetZoneIDByName retrieves a zone ID by its name
-func (c *Client) GetZoneIDByName(name string) (string, error) {
+// GetZoneByNameContext is like GetZoneByName but uses ctx for the request
+func (c *Client) GetZoneByNameContext(ctx context.Context, name string) (*Zone, error) {
 	// Normalize the input name
 	normalizedName := strings.ToLower(strings.TrimSuffix(name, "."))
 
-	// Get all zones
-	zones, err := c.GetZones()
-	if err != nil {
-		return "", err
+	zonesResp := &ZonesResponse{}
+	query := url.Values{"name": {normalizedName}}
+	if err := c.doJSON(ctx, "GET", "/zones?"+query.Encode(), nil, zonesResp); err != nil {
+		return nil, err
 	}
 
 	// Look for exact match
-	for _, zone := range zones {
+	for _, zone := range zonesResp.Zones {
 		zoneName := strings.ToLower(strings.TrimSuffix(zone.Name, "."))
 		if zoneName == normalizedName {
-			return zone.ID, nil
+			return &zone, nil
 		}
 	}
 
-	return "", fmt.Errorf("zone with name '%s' not found", name)
+	return nil, fmt.Errorf("%w: no zone named '%s'", ErrNotFound, name)
 }
 
-// GetRecords retrieves all DNS records for a zone
-func (c *Client) GetRecords(zoneID string) ([]Record, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/records?zone_id=%s", baseURL, zoneID), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Auth-API-Token", c.apiToken)
-	req.Header.Set("Content-Type", "application/json")
+// zoneRequest is the request body for creating and updating zones
+type zoneRequest struct {
+	Name string `json:"name"`
+	TTL  int    `json:"ttl,omitempty"`
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
+// GetZone retrieves a single DNS zone by its ID
+func (c *Client) GetZone(zoneID string) (*Zone, error) {
+	return c.GetZoneContext(context.Background(), zoneID)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("API error: %s, status code: %d", string(body), resp.StatusCode)
-	}
+// GetZoneContext is like GetZone but uses ctx for the request
+func (c *Client) GetZoneContext(ctx context.Context, zoneID string) (*Zone, error) {
+	return c.doZoneRequest(ctx, "GET", fmt.Sprintf("/zones/%s", zoneID), nil)
+}
 
-	// Use a map to avoid unmarshaling issues
-	var result map[string]interface{}
-	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return nil, err
-	}
+// CreateZone creates a new DNS zone. A ttl of zero uses the API default.
+func (c *Client) CreateZone(name string, ttl int) (*Zone, error) {
+	return c.CreateZoneContext(context.Background(), name, ttl)
+}
 
-	// Extract records from the response
-	recordsData, ok := result["records"].([]interface{})
-	if !ok {
-		return nil, fmt.Errorf("unexpected response format: records field not found or not an array")
-	}
+// CreateZoneContext is like CreateZone but uses ctx for the request
+func (c *Client) CreateZoneContext(ctx context.Context, name string, ttl int) (*Zone, error) {
+	return c.doZoneRequest(ctx, "POST", "/zones", &zoneRequest{Name: name, TTL: ttl})
+}
 
-	var records []Record
-	for _, recordData := range recordsData {
-		recordMap, ok := recordData.(map[string]interface{})
-		if !ok {
-			continue
-		}
+// UpdateZone updates the name and TTL of an existing DNS zone
+func (c *Client) UpdateZone(zone Zone) (*Zone, error) {
+	return c.UpdateZoneContext(context.Background(), zone)
+}
 
-		record := Record{}
+// UpdateZoneContext is like UpdateZone but uses ctx for the request
+func (c *Client) UpdateZoneContext(ctx context.Context, zone Zone) (*Zone, error) {
+	return c.doZoneRequest(ctx, "PUT", fmt.Sprintf("/zones/%s", zone.ID), &zoneRequest{Name: zone.Name, TTL: zone.TTL})
+}
 
-		// Extract ID
-		if id, ok := recordMap["id"].(string); ok {
-			record.ID = id
-		}
+// DeleteZone deletes a DNS zone together with all of its records
+func (c *Client) DeleteZone(zoneID string) error {
+	return c.DeleteZoneContext(context.Background(), zoneID)
+}
 
-		// Extract Type
-		if recordType, ok := recordMap["type"].(string); ok {
-			record.Type = recordType
-		}
+// DeleteZoneContext is like DeleteZone but uses ctx for the request
+func (c *Client) DeleteZoneContext(ctx context.Context, zoneID string) error {
+	return c.doJSON(ctx, "DELETE", fmt.Sprintf("/zones/%s", zoneID), nil, nil)
+}
 
-		// Extract Name
-		if name, ok := recordMap["name"].(string); ok {
-			record.Name = name
-		}
+// ExportZoneFile retrieves the zone as a BIND (RFC 1035) zone file
+func (c *Client) ExportZoneFile(zoneID string) (string, error) {
+	return c.ExportZoneFileContext(context.Background(), zoneID)
+}
 
-		// Extract Value
-		if value, ok := recordMap["value"].(string); ok {
-			record.Value = value
-		}
+// ExportZoneFileContext is like ExportZoneFile but uses ctx for the request
+func (c *Client) ExportZoneFileContext(ctx context.Context, zoneID string) (string, error) {
+	req, err := c.newRequest(ctx, "GET", fmt.Sprintf("/zones/%s/export", zoneID), nil)
+	if err != nil {
+		return "", err
+	}
 
-		// Extract TTL
-		if ttl, ok := recordMap["ttl"].(float64); ok {
-			record.TTL = int(ttl)
-		}
+	body, err := c.send(req)
+	if err != nil {
+		return "", err
+	}
 
-		// Extract ZoneID
-		if zoneID, ok := recordMap["zone_id"].(string); ok {
-			record.ZoneID = zoneID
-		}
+	return string(body), nil
+}
 
-		records = append(records, record)
-	}
+// ZoneFileValidation is the result of validating a zone file
+type ZoneFileValidation struct {
+	// ParsedRecords is the number of records found in the zone file
+	ParsedRecords int `json:"parsed_records"`
+	// ValidRecords are the records the API would import
+	ValidRecords []Record `json:"valid_records"`
+	// InvalidRecords are the records the API would reject
+	InvalidRecords []Record `json:"invalid_records,omitempty"`
+}
 
-	return records, nil
+// ValidateZoneFile checks a BIND zone file without importing it. Zone files
+// that cannot be parsed yield an *APIError matching ErrValidation.
+func (c *Client) ValidateZoneFile(zoneFile string) (*ZoneFileValidation, error) {
+	return c.ValidateZoneFileContext(context.Background(), zoneFile)
 }
 
-// CreateRecord creates a new DNS record
-func (c *Client) CreateRecord(record Record) (*Record, error) {
-	recordJSON, err := json.Marshal(record)
+// ValidateZoneFileContext is like ValidateZoneFile but uses ctx for the request
+func (c *Client) ValidateZoneFileContext(ctx context.Context, zoneFile string) (*ZoneFileValidation, error) {
+	req, err := c.newRequest(ctx, "POST", "/zones/file/validate", strings.NewReader(zoneFile))
 	if err != nil {
 		return nil, err
 	}
+	req.Header.Set("Content-Type", "text/plain")
 
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/records", baseURL), bytes.NewBuffer(recordJSON))
+	body, err := c.send(req)
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Auth-API-Token", c.apiToken)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
+	validation := &ZoneFileValidation{}
+	if err := decodeJSON(body, validation); err != nil {
 		return nil, err
 	}
-	defer resp.Body.Close()
+	return validation, nil
+}
 
-	body, _ := io.ReadAll(resp.Body)
+// ImportZoneFile replaces all records of a zone with the records of a BIND zone file
+func (c *Client) ImportZoneFile(zoneID, zoneFile string) (*Zone, error) {
+	return c.ImportZoneFileContext(context.Background(), zoneID, zoneFile)
+}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("API error: %s, status code: %d", string(body), resp.StatusCode)
+// ImportZoneFileContext is like ImportZoneFile but uses ctx for the request
+func (c *Client) ImportZoneFileContext(ctx context.Context, zoneID, zoneFile string) (*Zone, error) {
+	req, err := c.newRequest(ctx, "POST", fmt.Sprintf("/zones/%s/import", zoneID), strings.NewReader(zoneFile))
+	if err != nil {
+		return nil, err
 	}
+	req.Header.Set("Content-Type", "text/plain")
 
-	// Use a map to avoid unmarshaling issues
-	var result map[string]interface{}
-	if err := json.Unmarshal(body, &result); err != nil {
+	body, err := c.send(req)
+	if err != nil {
 		return nil, err
 	}
 
-	// Extract record from the response
-	recordData, ok := result["record"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("unexpected response format: record field not found or not an object")
+	var result zoneEnvelope
+	if err := decodeJSON(body, &result); err != nil {
+		return nil, err
 	}
-
-	createdRecord := &Record{}
-
-	// Extract ID
-	if id, ok := recordData["id"].(string); ok {
-		createdRecord.ID = id
+	if result.Zone == nil {
+		return nil, &DecodeError{Path: "zone", Err: errMissingField}
 	}
+	return result.Zone, nil
+}
 
-	// Extract Type
-	if recordType, ok := recordData["type"].(string); ok {
-		createdRecord.Type = recordType
+// doZoneRequest sends a request whose response holds a single zone
+func (c *Client) doZoneRequest(ctx context.Context, method, path string, payload interface{}) (*Zone, error) {
+	var result zoneEnvelope
+	if err := c.doJSON(ctx, method, path, payload, &result); err != nil {
+		return nil, err
 	}
-
-	// Extract Name
-	if name, ok := recordData["name"].(string); ok {
-		createdRecord.Name = name
+	if result.Zone == nil {
+		return nil, &DecodeError{Path: "zone", Err: errMissingField}
 	}
+	return result.Zone, nil
+}
 
-	// Extract Value
-	if value, ok := recordData["value"].(string); ok {
-		createdRecord.Value = value
-	}
+// GetRecords retrieves all DNS records for a zone, following pagination until the last page
+func (c *Client) GetRecords(zoneID string) ([]Record, error) {
+	return c.GetRecordsContext(context.Background(), zoneID)
+}
 
-	// Extract TTL
-	if ttl, ok := recordData["ttl"].(float64); ok {
-		createdRecord.TTL = int(ttl)
-	}
+// GetRecordsContext is like GetRecords but uses ctx for all requests
+func (c *Client) GetRecordsContext(ctx context.Context, zoneID string) ([]Record, error) {
+	var records []Record
+	for page := 1; ; page++ {
+		resp, err := c.GetRecordsPageContext(ctx, zoneID, page, c.perPage)
+		if err != nil {
+			return nil, err
+		}
 
-	// Extract ZoneID
-	if zoneID, ok := recordData["zone_id"].(string); ok {
-		createdRecord.ZoneID = zoneID
+		records = append(records, resp.Records...)
+		if isLastPage(resp.Meta.Pagination, page, len(resp.Records)) {
+			return records, nil
+		}
 	}
+}
 
-	return createdRecord, nil
+// GetRecordsPage retrieves a single page of DNS records for a zone. A perPage
+// of zero or less uses the API default.
+func (c *Client) GetRecordsPage(zoneID string, page, perPage int) (*RecordsResponse, error) {
+	return c.GetRecordsPageContext(context.Background(), zoneID, page, perPage)
 }
 
-// UpdateRecord updates an existing DNS record
-func (c *Client) UpdateRecord(record Record) (*Record, error) {
-	recordJSON, err := json.Marshal(record)
-	if err != nil {
+// GetRecordsPageContext is like GetRecordsPage but uses ctx for the request
+func (c *Client) GetRecordsPageContext(ctx context.Context, zoneID string, page, perPage int) (*RecordsResponse, error) {
+	query := url.Values{"zone_id": {zoneID}}
+	recordsResp := &RecordsResponse{}
+	if err := c.doJSON(ctx, "GET", "/records"+pageQuery(query, page, perPage), nil, recordsResp); err != nil {
 		return nil, err
 	}
+	return recordsResp, nil
+}
 
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/records/%s", baseURL, record.ID), bytes.NewBuffer(recordJSON))
-	if err != nil {
-		return nil, err
+// pageQuery appends the page and per_page parameters to query and encodes it
+// as a URL query string including the leading "?"
+func pageQuery(query url.Values, page, perPage int) string {
+	if query == nil {
+		query = url.Values{}
 	}
-
-	req.Header.Set("Auth-API-Token", c.apiToken)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
+	if page > 0 {
+		query.Set("page", strconv.Itoa(page))
 	}
-	defer resp.Body.Close()
+	if perPage > 0 {
+		query.Set("per_page", strconv.Itoa(perPage))
+	}
+	if len(query) == 0 {
+		return ""
+	}
+	return "?" + query.Encode()
+}
 
-	body, _ := io.ReadAll(resp.Body)
+// isLastPage reports whether page is the final page of a listing. Responses
+// without pagination metadata are treated as a single page.
+func isLastPage(p Pagination, page, count int) bool {
+	return count == 0 || p.LastPage <= page
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API error: %s, status code: %d", string(body), resp.StatusCode)
-	}
+// timestampLayouts are the formats timestamps are reported in. The API uses
+// Go's default time.Time format, e.g. "2018-08-23 13:21:39.489 +0000 UTC".
+var timestampLayouts = []string{
+	"2006-01-02 15:04:05.999999999 -0700 MST",
+	"2006-01-02 15:04:05.999999999 -0700",
+	time.RFC3339Nano,
+}
 
-	// Use a map to avoid unmarshaling issues
-	var result map[string]interface{}
-	if err := json.Unmarshal(body, &result); err != nil {
-		return nil, err
+// parseTimestamp parses a timestamp as reported by the API. An empty string
+// yields the zero time.
+func parseTimestamp(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
 	}
 
-	// Extract record from the response
-	recordData, ok := result["record"].(map[string]interface{})
-	if !ok {
-		return nil, fmt.Errorf("unexpected response format: record field not found or not an object")
+	var err error
+	for _, layout := range timestampLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, value); err == nil {
+			return t, nil
+		}
 	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q: %w", value, err)
+}
 
-	updatedRecord := &Record{}
+// GetRecord retrieves a single DNS record by its ID
+func (c *Client) GetRecord(recordID string) (*Record, error) {
+	return c.GetRecordContext(context.Background(), recordID)
+}
 
-	// Extract ID
-	if id, ok := recordData["id"].(string); ok {
-		updatedRecord.ID = id
-	}
+// GetRecordContext is like GetRecord but uses ctx for the request
+func (c *Client) GetRecordContext(ctx context.Context, recordID string) (*Record, error) {
+	return c.doRecordRequest(ctx, "GET", fmt.Sprintf("/records/%s", recordID), nil)
+}
 
-	// Extract Type
-	if recordType, ok := recordData["type"].(string); ok {
-		updatedRecord.Type = recordType
-	}
+// CreateRecord creates a new DNS record
+func (c *Client) CreateRecord(record Record) (*Record, error) {
+	return c.CreateRecordContext(context.Background(), record)
+}
 
-	// Extract Name
-	if name, ok := recordData["name"].(string); ok {
-		updatedRecord.Name = name
-	}
+// CreateRecordContext is like CreateRecord but uses ctx for the request
+func (c *Client) CreateRecordContext(ctx context.Context, record Record) (*Record, error) {
+	return c.doRecordRequest(ctx, "POST", "/records", newRecordRequest(record))
+}
 
-	// Extract Value
-	if value, ok := recordData["value"].(string); ok {
-		updatedRecord.Value = value
-	}
+// UpdateRecord updates an existing DNS record
+func (c *Client) UpdateRecord(record Record) (*Record, error) {
+	return c.UpdateRecordContext(context.Background(), record)
+}
 
-	// Extract TTL
-	if ttl, ok := recordData["ttl"].(float64); ok {
-		updatedRecord.TTL = int(ttl)
-	}
+// UpdateRecordContext is like UpdateRecord but uses ctx for the request
+func (c *Client) UpdateRecordContext(ctx context.Context, record Record) (*Record, error) {
+	return c.doRecordRequest(ctx, "PUT", fmt.Sprintf("/records/%s", record.ID), newRecordRequest(record))
+}
 
-	// Extract ZoneID
-	if zoneID, ok := recordData["zone_id"].(string); ok {
-		updatedRecord.ZoneID = zoneID
+// doRecordRequest sends a request whose response holds a single record
+func (c *Client) doRecordRequest(ctx context.Context, method, path string, payload interface{}) (*Record, error) {
+	var result recordEnvelope
+	if err := c.doJSON(ctx, method, path, payload, &result); err != nil {
+		return nil, err
 	}
+	if result.Record == nil {
+		return nil, &DecodeError{Path: "record", Err: errMissingField}
+	}
+	return result.Record, nil
+}
 
-	return updatedRecord, nil
+// BulkCreateResult is the result of creating several records at once
+type BulkCreateResult struct {
+	// Records are the records that were created
+	Records []Record `json:"records"`
+	// ValidRecords are the submitted records the API accepted
+	ValidRecords []Record `json:"valid_records"`
+	// InvalidRecords are the submitted records the API rejected
+	InvalidRecords []Record `json:"invalid_records"`
 }
 
-// DeleteRecord deletes a DNS record
-func (c *Client) DeleteRecord(recordID string) error {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/records/%s", baseURL, recordID), nil)
-	if err != nil {
-		return err
+// BulkUpdateResult is the result of updating several records at once
+type BulkUpdateResult struct {
+	// Records are the records that were updated
+	Records []Record `json:"records"`
+	// FailedRecords are the submitted records that could not be updated
+	FailedRecords []Record `json:"failed_records"`
+}
+
+// bulkRecordsRequest is the request body of the bulk records endpoints
+type bulkRecordsRequest struct {
+	Records []recordRequest `json:"records"`
+}
+
+// newBulkRecordsRequest builds the request body of the bulk records endpoints
+func newBulkRecordsRequest(records []Record) bulkRecordsRequest {
+	payload := bulkRecordsRequest{Records: make([]recordRequest, len(records))}
+	for i, record := range records {
+		payload.Records[i] = newRecordRequest(record)
 	}
+	return payload
+}
 
-	req.Header.Set("Auth-API-Token", c.apiToken)
+// CreateRecords creates several DNS records with a single request. Records
+// the API rejects are reported in the result instead of failing the call.
+func (c *Client) CreateRecords(records []Record) (*BulkCreateResult, error) {
+	return c.CreateRecordsContext(context.Background(), records)
+}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return err
+// CreateRecordsContext is like CreateRecords but uses ctx for the request
+func (c *Client) CreateRecordsContext(ctx context.Context, records []Record) (*BulkCreateResult, error) {
+	result := &BulkCreateResult{}
+	if err := c.doJSON(ctx, "POST", "/records/bulk", newBulkRecordsRequest(records), result); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
+	return result, nil
+}
 
-	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("API error: %s, status code: %d", string(body), resp.StatusCode)
+// UpdateRecords updates several DNS records, identified by their IDs, with a
+// single request. Records that could not be updated are reported in the
+// result instead of failing the call.
+func (c *Client) UpdateRecords(records []Record) (*BulkUpdateResult, error) {
+	return c.UpdateRecordsContext(context.Background(), records)
+}
+
+// UpdateRecordsContext is like UpdateRecords but uses ctx for the request
+func (c *Client) UpdateRecordsContext(ctx context.Context, records []Record) (*BulkUpdateResult, error) {
+	result := &BulkUpdateResult{}
+	if err := c.doJSON(ctx, "PUT", "/records/bulk", newBulkRecordsRequest(records), result); err != nil {
+		return nil, err
 	}
+	return result, nil
+}
+
+// DeleteRecord deletes a DNS record
+func (c *Client) DeleteRecord(recordID string) error {
+	return c.DeleteRecordContext(context.Background(), recordID)
+}
 
-	return nil
+// DeleteRecordContext is like DeleteRecord but uses ctx for the request
+func (c *Client) DeleteRecordContext(ctx context.Context, recordID string) error {
+	return c.doJSON(ctx, "DELETE", fmt.Sprintf("/records/%s", recordID), nil, nil)
 }
@@ -1,156 +1,20 @@
 package api
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"testing"
+	"time"
 )
 
-// testClient extends the Client with a custom baseURL for testing
-type testClient struct {
-	*Client
-	testBaseURL string
-}
-
-// Override methods that use baseURL to use the test server URL instead
-func (c *testClient) GetZones() ([]Zone, error) {
-	req, err := http.NewRequest("GET", fmt.Sprintf("%s/zones", c.testBaseURL), nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Auth-API-Token", c.apiToken)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status code %d", resp.StatusCode)
-	}
-
-	var zonesResp ZonesResponse
-	if err := json.NewDecoder(resp.Body).Decode(&zonesResp); err != nil {
-		return nil, err
-	}
-
-	return zonesResp.Zones, nil
-}
-
-func (c *testClient) GetZoneIDByName(name string) (string, error) {
-	zones, err := c.GetZones()
-	if err != nil {
-		return "", err
-	}
-
-	for _, zone := range zones {
-		if zone.Name == name {
-			return zone.ID, nil
-		}
-	}
-
-	return "", fmt.Errorf("zone with name %s not found", name)
-}
-
-func (c *testClient) CreateRecord(record Record) (*Record, error) {
-	recordJSON, err := json.Marshal(record)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("POST", fmt.Sprintf("%s/records", c.testBaseURL), bytes.NewBuffer(recordJSON))
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Auth-API-Token", c.apiToken)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status code %d", resp.StatusCode)
-	}
-
-	var recordResp RecordResponse
-	if err := json.NewDecoder(resp.Body).Decode(&recordResp); err != nil {
-		return nil, err
-	}
-
-	return &recordResp.Record, nil
-}
-
-func (c *testClient) UpdateRecord(record Record) (*Record, error) {
-	recordJSON, err := json.Marshal(record)
-	if err != nil {
-		return nil, err
-	}
-
-	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/records/%s", c.testBaseURL, record.ID), bytes.NewBuffer(recordJSON))
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Auth-API-Token", c.apiToken)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status code %d", resp.StatusCode)
-	}
-
-	var recordResp RecordResponse
-	if err := json.NewDecoder(resp.Body).Decode(&recordResp); err != nil {
-		return nil, err
-	}
-
-	return &recordResp.Record, nil
-}
-
-func (c *testClient) DeleteRecord(recordID string) error {
-	req, err := http.NewRequest("DELETE", fmt.Sprintf("%s/records/%s", c.testBaseURL, recordID), nil)
-	if err != nil {
-		return err
-	}
-
-	req.Header.Set("Auth-API-Token", c.apiToken)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("API request failed with status code %d", resp.StatusCode)
-	}
-
-	return nil
-}
-
-// newTestClient creates a new test client with the given server
-func newTestClient(server *httptest.Server) *testClient {
-	client := NewClient("test-token")
-	client.httpClient = server.Client()
-	return &testClient{
-		Client:      client,
-		testBaseURL: server.URL,
-	}
+// newTestClient creates a client that talks to the given test server
+func newTestClient(server *httptest.Server) *Client {
+	return NewClient("test-token", WithBaseURL(server.URL), WithHTTPClient(server.Client()))
 }
 
 func setupTestServer(t *testing.T, path string, statusCode int, response interface{}) *httptest.Server {
@@ -185,6 +49,76 @@ func TestNewClient(t *testing.T) {
 	if client.httpClient == nil {
 		t.Error("Expected httpClient to be initialized")
 	}
+	if client.baseURL != DefaultBaseURL {
+		t.Errorf("Expected baseURL to be '%s', got '%s'", DefaultBaseURL, client.baseURL)
+	}
+	if client.httpClient.Timeout != DefaultTimeout {
+		t.Errorf("Expected timeout %v, got %v", DefaultTimeout, client.httpClient.Timeout)
+	}
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestNewClientOptions(t *testing.T) {
+	var gotUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserAgent = r.Header.Get("User-Agent")
+		if r.URL.Path != "/custom/zones" {
+			t.Errorf("Expected path /custom/zones, got %s", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ZonesResponse{Zones: []Zone{}})
+	}))
+	defer server.Close()
+
+	base := server.Client()
+	client := NewClient("test-token",
+		WithBaseURL(server.URL+"/custom/"),
+		WithHTTPClient(base),
+		WithTimeout(3*time.Second),
+		WithUserAgent("my-agent/1.0"),
+	)
+
+	if _, err := client.GetZones(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if gotUserAgent != "my-agent/1.0" {
+		t.Errorf("Expected User-Agent 'my-agent/1.0', got '%s'", gotUserAgent)
+	}
+	if client.httpClient.Timeout != 3*time.Second {
+		t.Errorf("Expected timeout 3s, got %v", client.httpClient.Timeout)
+	}
+	if base.Timeout == 3*time.Second {
+		t.Error("Expected the caller's http.Client to be left unmodified")
+	}
+}
+
+func TestWithTransport(t *testing.T) {
+	called := false
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		called = true
+		if req.URL.String() != "https://proxy.example/api/records/record1" {
+			t.Errorf("Unexpected request URL %s", req.URL)
+		}
+		return &http.Response{
+			StatusCode: http.StatusNoContent,
+			Body:       http.NoBody,
+			Header:     make(http.Header),
+			Request:    req,
+		}, nil
+	})
+
+	client := NewClient("test-token", WithBaseURL("https://proxy.example/api"), WithTransport(transport))
+	if err := client.DeleteRecord("record1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !called {
+		t.Error("Expected custom transport to be used")
+	}
 }
 
 func TestGetZones(t *testing.T) {
@@ -268,6 +202,27 @@ func TestGetZoneIDByName(t *testing.T) {
 	}
 }
 
+func TestGetZoneByNameUsesNameFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if name := r.URL.Query().Get("name"); name != "example.com" {
+			t.Errorf("Expected name filter 'example.com', got %q", name)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"zones":[{"id":"zone1","name":"example.com"}]}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	zone, err := client.GetZoneByName("Example.COM.")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if zone.ID != "zone1" {
+		t.Errorf("Expected zone ID 'zone1', got '%s'", zone.ID)
+	}
+}
+
 func TestCreateRecord(t *testing.T) {
 	// Setup test record
 	record := Record{
@@ -343,6 +298,42 @@ func TestUpdateRecord(t *testing.T) {
 	}
 }
 
+func TestGetRecord(t *testing.T) {
+	// Setup expected response
+	recordResponse := RecordResponse{
+		Record: Record{
+			ID:       "record1",
+			Type:     "A",
+			Name:     "www",
+			Value:    "192.168.1.1",
+			TTL:      3600,
+			ZoneID:   "zone1",
+			Created:  time.Date(2024, 1, 2, 3, 4, 5, 123000000, time.UTC),
+			Modified: time.Date(2024, 2, 3, 4, 5, 6, 456000000, time.UTC),
+		},
+	}
+
+	// Setup test server
+	server := setupTestServer(t, "/records/record1", http.StatusOK, recordResponse)
+	defer server.Close()
+
+	// Create test client
+	client := newTestClient(server)
+
+	// Call the method
+	record, err := client.GetRecord("record1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Check results
+	expected := recordResponse.Record
+	if record.ID != expected.ID || record.Value != expected.Value || record.TTL != expected.TTL ||
+		!record.Created.Equal(expected.Created) || !record.Modified.Equal(expected.Modified) {
+		t.Errorf("Record data doesn't match expected values: %+v", record)
+	}
+}
+
 func TestDeleteRecord(t *testing.T) {
 	// Setup test server
 	server := setupTestServer(t, "/records/record1", http.StatusOK, nil)
@@ -357,3 +348,428 @@ func TestDeleteRecord(t *testing.T) {
 		t.Fatalf("Expected no error, got %v", err)
 	}
 }
+
+func TestGetZonesPagination(t *testing.T) {
+	allZones := []Zone{
+		{ID: "zone1", Name: "a.example"},
+		{ID: "zone2", Name: "b.example"},
+		{ID: "zone3", Name: "c.example"},
+		{ID: "zone4", Name: "d.example"},
+		{ID: "zone5", Name: "e.example"},
+	}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Query().Get("per_page") != "2" {
+			t.Errorf("Expected per_page=2, got %s", r.URL.Query().Get("per_page"))
+		}
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		start := (page - 1) * 2
+		end := min(start+2, len(allZones))
+
+		resp := ZonesResponse{Zones: allZones[start:end]}
+		resp.Meta.Pagination = Pagination{Page: page, PerPage: 2, LastPage: 3, TotalEntries: len(allZones)}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := NewClient("test-token", WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithPerPage(2))
+
+	zones, err := client.GetZones()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(zones) != 5 {
+		t.Fatalf("Expected 5 zones, got %d", len(zones))
+	}
+	if zones[4].ID != "zone5" {
+		t.Errorf("Expected last zone 'zone5', got '%s'", zones[4].ID)
+	}
+	if requests != 3 {
+		t.Errorf("Expected 3 requests, got %d", requests)
+	}
+}
+
+func TestGetRecordsPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("zone_id") != "zone1" || query.Get("page") != "2" || query.Get("per_page") != "1" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"records":[{"id":"record2","type":"A","name":"www","value":"192.0.2.1","zone_id":"zone1"}],
+			"meta":{"pagination":{"page":2,"per_page":1,"last_page":4,"total_entries":4}}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	resp, err := client.GetRecordsPage("zone1", 2, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(resp.Records) != 1 || resp.Records[0].ID != "record2" {
+		t.Errorf("Record data doesn't match expected values")
+	}
+	if resp.Meta.Pagination.LastPage != 4 || resp.Meta.Pagination.TotalEntries != 4 {
+		t.Errorf("Unexpected pagination %+v", resp.Meta.Pagination)
+	}
+}
+
+func TestContextCancellation(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := newTestClient(server)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := client.GetZonesContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if err := client.DeleteRecordContext(ctx, "record1"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestCreateZone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/zones" {
+			t.Errorf("Expected POST /zones, got %s %s", r.Method, r.URL.Path)
+		}
+
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["name"] != "example.com" || body["ttl"] != float64(3600) {
+			t.Errorf("Unexpected request body %v", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(ZoneResponse{Zone: Zone{ID: "zone1", Name: "example.com", TTL: 3600}})
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	zone, err := client.CreateZone("example.com", 3600)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if zone.ID != "zone1" || zone.Name != "example.com" || zone.TTL != 3600 {
+		t.Errorf("Zone data doesn't match expected values: %+v", zone)
+	}
+}
+
+func TestGetZone(t *testing.T) {
+	server := setupTestServer(t, "/zones/zone1", http.StatusOK, ZoneResponse{Zone: Zone{ID: "zone1", Name: "example.com", TTL: 86400, RecordsCount: 4}})
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	zone, err := client.GetZone("zone1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if zone.ID != "zone1" || zone.RecordsCount != 4 {
+		t.Errorf("Zone data doesn't match expected values: %+v", zone)
+	}
+}
+
+func TestUpdateZone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/zones/zone1" {
+			t.Errorf("Expected PUT /zones/zone1, got %s %s", r.Method, r.URL.Path)
+		}
+
+		var body zoneRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(ZoneResponse{Zone: Zone{ID: "zone1", Name: body.Name, TTL: body.TTL}})
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	zone, err := client.UpdateZone(Zone{ID: "zone1", Name: "example.com", TTL: 600})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if zone.TTL != 600 || zone.Name != "example.com" {
+		t.Errorf("Zone data doesn't match expected values: %+v", zone)
+	}
+}
+
+func TestDeleteZone(t *testing.T) {
+	server := setupTestServer(t, "/zones/zone1", http.StatusOK, nil)
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	if err := client.DeleteZone("zone1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestGetZoneFullModel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"zone":{
+			"id":"zone1","name":"example.com","ttl":86400,"records_count":7,
+			"created":"2018-08-23 13:21:39.489 +0000 UTC",
+			"modified":"2020-01-02 03:04:05 +0000 UTC",
+			"verified":"",
+			"status":"pending",
+			"ns":["hydrogen.ns.hetzner.com","oxygen.ns.hetzner.com","helium.ns.hetzner.de"],
+			"legacy_dns_host":"ns1.other.example",
+			"legacy_ns":["ns1.other.example","ns2.other.example"],
+			"is_secondary_dns":true,
+			"txt_verification":{"name":"_hetzner","token":"abc123"},
+			"owner":"Jane Doe","project":"web","permission":"owner","registrar":"","paused":false,
+			"zone_type":{"id":"1","name":"standard"}
+		}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	zone, err := client.GetZone("zone1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if zone.Status != ZoneStatusPending {
+		t.Errorf("Expected status pending, got '%s'", zone.Status)
+	}
+	if len(zone.NS) != 3 || zone.NS[0] != "hydrogen.ns.hetzner.com" {
+		t.Errorf("Unexpected name servers %v", zone.NS)
+	}
+	if want := time.Date(2018, 8, 23, 13, 21, 39, 489000000, time.UTC); !zone.Created.Equal(want) {
+		t.Errorf("Expected created %v, got %v", want, zone.Created)
+	}
+	if zone.Modified.Year() != 2020 {
+		t.Errorf("Expected modified in 2020, got %v", zone.Modified)
+	}
+	if !zone.Verified.IsZero() {
+		t.Errorf("Expected zero verified time, got %v", zone.Verified)
+	}
+	if !zone.IsSecondaryDNS {
+		t.Error("Expected is_secondary_dns to be true")
+	}
+	if zone.TxtVerification == nil || zone.TxtVerification.Token != "abc123" {
+		t.Errorf("Unexpected txt verification %+v", zone.TxtVerification)
+	}
+	if zone.LegacyDNSHost != "ns1.other.example" || len(zone.LegacyNS) != 2 {
+		t.Errorf("Unexpected legacy name servers %s %v", zone.LegacyDNSHost, zone.LegacyNS)
+	}
+	if zone.Owner != "Jane Doe" || zone.Project != "web" || zone.Permission != "owner" {
+		t.Errorf("Unexpected ownership details %+v", zone)
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2018-08-23 13:21:39.489 +0000 UTC", time.Date(2018, 8, 23, 13, 21, 39, 489000000, time.UTC)},
+		{"2021-03-04 05:06:07 +0000 UTC", time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)},
+		{"2021-03-04T05:06:07Z", time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)},
+		{"", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		got, err := parseTimestamp(tt.value)
+		if err != nil {
+			t.Errorf("parseTimestamp(%q) returned error %v", tt.value, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseTimestamp(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+
+	if _, err := parseTimestamp("yesterday"); err == nil {
+		t.Error("Expected an error for an invalid timestamp")
+	}
+}
+
+func TestExportZoneFile(t *testing.T) {
+	zoneFile := "$ORIGIN example.com.\n$TTL 86400\n@ IN SOA hydrogen.ns.hetzner.com. dns.hetzner.com. 2024010101 86400 10800 3600000 3600\nwww IN A 192.0.2.1\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" || r.URL.Path != "/zones/zone1/export" {
+			t.Errorf("Expected GET /zones/zone1/export, got %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(zoneFile))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	got, err := client.ExportZoneFile("zone1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got != zoneFile {
+		t.Errorf("Expected zone file %q, got %q", zoneFile, got)
+	}
+}
+
+func TestValidateZoneFile(t *testing.T) {
+	zoneFile := "$ORIGIN example.com.\nwww IN A 192.0.2.1\nmail IN MX mail.example.com.\n"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/zones/file/validate" {
+			t.Errorf("Expected POST /zones/file/validate, got %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Content-Type") != "text/plain" {
+			t.Errorf("Expected Content-Type text/plain, got %s", r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != zoneFile {
+			t.Errorf("Expected zone file in request body, got %q", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"parsed_records":2,
+			"valid_records":[{"type":"A","name":"www","value":"192.0.2.1","ttl":0}],
+			"invalid_records":[{"type":"MX","name":"mail","value":"mail.example.com."}]}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	validation, err := client.ValidateZoneFile(zoneFile)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if validation.ParsedRecords != 2 {
+		t.Errorf("Expected 2 parsed records, got %d", validation.ParsedRecords)
+	}
+	if len(validation.ValidRecords) != 1 || validation.ValidRecords[0].Value != "192.0.2.1" {
+		t.Errorf("Unexpected valid records %+v", validation.ValidRecords)
+	}
+	if len(validation.InvalidRecords) != 1 || validation.InvalidRecords[0].Type != "MX" {
+		t.Errorf("Unexpected invalid records %+v", validation.InvalidRecords)
+	}
+}
+
+func TestValidateZoneFileParseError(t *testing.T) {
+	server := setupTestServer(t, "/zones/file/validate", http.StatusUnprocessableEntity, map[string]interface{}{
+		"error": map[string]interface{}{"message": "invalid zone file", "code": 422},
+	})
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	if _, err := client.ValidateZoneFile("garbage"); !errors.Is(err, ErrValidation) {
+		t.Errorf("Expected ErrValidation, got %v", err)
+	}
+}
+
+func TestImportZoneFile(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/zones/zone1/import" {
+			t.Errorf("Expected POST /zones/zone1/import, got %s %s", r.Method, r.URL.Path)
+		}
+		if r.Header.Get("Content-Type") != "text/plain" {
+			t.Errorf("Expected Content-Type text/plain, got %s", r.Header.Get("Content-Type"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"zone":{"id":"zone1","name":"example.com","records_count":3}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	zone, err := client.ImportZoneFile("zone1", "$ORIGIN example.com.\n")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if zone.ID != "zone1" || zone.RecordsCount != 3 {
+		t.Errorf("Zone data doesn't match expected values: %+v", zone)
+	}
+}
+
+func TestCreateRecords(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/records/bulk" {
+			t.Errorf("Expected POST /records/bulk, got %s %s", r.Method, r.URL.Path)
+		}
+
+		var body bulkRecordsRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		if len(body.Records) != 2 {
+			t.Errorf("Expected 2 records in request, got %d", len(body.Records))
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"records":[{"id":"record1","type":"A","name":"www","value":"192.0.2.1","zone_id":"zone1"}],
+			"valid_records":[{"type":"A","name":"www","value":"192.0.2.1","zone_id":"zone1"}],
+			"invalid_records":[{"type":"AAAA","name":"www","value":"nope","zone_id":"zone1"}]}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	result, err := client.CreateRecords([]Record{
+		{Type: "A", Name: "www", Value: "192.0.2.1", ZoneID: "zone1"},
+		{Type: "AAAA", Name: "www", Value: "nope", ZoneID: "zone1"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Records) != 1 || result.Records[0].ID != "record1" {
+		t.Errorf("Unexpected created records %+v", result.Records)
+	}
+	if len(result.ValidRecords) != 1 {
+		t.Errorf("Expected 1 valid record, got %d", len(result.ValidRecords))
+	}
+	if len(result.InvalidRecords) != 1 || result.InvalidRecords[0].Value != "nope" {
+		t.Errorf("Unexpected invalid records %+v", result.InvalidRecords)
+	}
+}
+
+func TestUpdateRecords(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/records/bulk" {
+			t.Errorf("Expected PUT /records/bulk, got %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+			"records":[{"id":"record1","type":"A","name":"www","value":"192.0.2.2","zone_id":"zone1"}],
+			"failed_records":[{"id":"record2","type":"A","name":"mail","value":"192.0.2.3","zone_id":"zone1"}]}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	result, err := client.UpdateRecords([]Record{
+		{ID: "record1", Type: "A", Name: "www", Value: "192.0.2.2", ZoneID: "zone1"},
+		{ID: "record2", Type: "A", Name: "mail", Value: "192.0.2.3", ZoneID: "zone1"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Records) != 1 || result.Records[0].Value != "192.0.2.2" {
+		t.Errorf("Unexpected updated records %+v", result.Records)
+	}
+	if len(result.FailedRecords) != 1 || result.FailedRecords[0].ID != "record2" {
+		t.Errorf("Unexpected failed records %+v", result.FailedRecords)
+	}
+}
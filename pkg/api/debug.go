@@ -0,0 +1,347 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// redactedHeaders are the request headers whose values are never logged or recorded
+var redactedHeaders = []string{"Auth-API-Token"}
+
+// redactedValue replaces the values of redacted headers
+const redactedValue = "[REDACTED]"
+
+// WithDebugLog logs every request and response, including headers, bodies
+// and latency, to w. The API token is always redacted.
+func WithDebugLog(w io.Writer) Option {
+	return func(o *clientOptions) {
+		o.debugLog = w
+	}
+}
+
+// WithHARRecorder records every request and response in har, e.g. to attach
+// the session to a support ticket. The API token is always redacted.
+func WithHARRecorder(har *HARRecorder) Option {
+	return func(o *clientOptions) {
+		o.har = har
+	}
+}
+
+// debugTransport is the RoundTripper installed by WithDebugLog and
+// WithHARRecorder. It wraps the transport that sends the requests, so every
+// retry is traced as a request of its own.
+type debugTransport struct {
+	base http.RoundTripper
+	log  io.Writer
+	har  *HARRecorder
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, sent, err := peekRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	resp, err := t.base.RoundTrip(sent)
+	latency := time.Since(started)
+
+	var respBody []byte
+	if err == nil {
+		resp.Request = req
+		respBody, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(respBody))
+		if err != nil {
+			resp = nil
+		}
+	}
+
+	if t.log != nil {
+		t.logExchange(req, reqBody, resp, respBody, latency, err)
+	}
+	if t.har != nil {
+		t.har.add(req, reqBody, resp, respBody, started, latency)
+	}
+
+	return resp, err
+}
+
+// logExchange writes a request and its response or error to the debug log
+func (t *debugTransport) logExchange(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, latency time.Duration, err error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--> %s %s\n", req.Method, req.URL)
+	writeHeaders(&buf, redactHeaders(req.Header))
+	writeBody(&buf, reqBody)
+
+	if err != nil {
+		fmt.Fprintf(&buf, "<-- error after %s: %v\n\n", latency.Round(time.Millisecond), err)
+	} else {
+		fmt.Fprintf(&buf, "<-- %s (%s)\n", resp.Status, latency.Round(time.Millisecond))
+		writeHeaders(&buf, resp.Header)
+		writeBody(&buf, respBody)
+		buf.WriteByte('\n')
+	}
+
+	t.log.Write(buf.Bytes())
+}
+
+// peekRequestBody returns the body of req and the request to send in its
+// place. The body is read from a copy made with GetBody if possible, so req
+// is sent as is. Otherwise req.Body is consumed and a clone of req carrying
+// the body read is returned; RoundTrippers must not modify the request.
+func peekRequestBody(req *http.Request) ([]byte, *http.Request, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req, nil
+	}
+
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, nil, err
+		}
+		defer body.Close()
+		data, err := io.ReadAll(body)
+		return data, req, err
+	}
+
+	data, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(data))
+	clone.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	return data, clone, nil
+}
+
+// redactHeaders returns a copy of header with the values of secret headers replaced
+func redactHeaders(header http.Header) http.Header {
+	redacted := header.Clone()
+	for _, name := range redactedHeaders {
+		if redacted.Get(name) != "" {
+			redacted.Set(name, redactedValue)
+		}
+	}
+	return redacted
+}
+
+// writeHeaders writes header sorted by name, one per line
+func writeHeaders(w io.Writer, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range header[name] {
+			fmt.Fprintf(w, "%s: %s\n", name, value)
+		}
+	}
+}
+
+// writeBody writes a request or response body separated by an empty line
+func writeBody(w io.Writer, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s\n", bytes.TrimRight(body, "\n"))
+}
+
+// HARRecorder collects requests and responses in the HTTP Archive (HAR) 1.2
+// format, which browsers and many HTTP tools can display
+type HARRecorder struct {
+	creator string
+
+	mu      sync.Mutex
+	entries []harEntry
+}
+
+// NewHARRecorder creates an empty HARRecorder. creator names the program in
+// the recorded archive.
+func NewHARRecorder(creator string) *HARRecorder {
+	return &HARRecorder{creator: creator}
+}
+
+// Len returns the number of recorded requests
+func (h *HARRecorder) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.entries)
+}
+
+// WriteTo writes the recorded requests as a HAR document to w
+func (h *HARRecorder) WriteTo(w io.Writer) (int64, error) {
+	h.mu.Lock()
+	doc := harDocument{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: h.creator, Version: "1.0"},
+		Entries: append([]harEntry{}, h.entries...),
+	}}
+	h.mu.Unlock()
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	n, err := w.Write(append(data, '\n'))
+	return int64(n), err
+}
+
+// Save writes the recorded requests as a HAR document to the file at path
+func (h *HARRecorder) Save(path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := h.WriteTo(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// add records a request and its response, if there is one
+func (h *HARRecorder) add(req *http.Request, reqBody []byte, resp *http.Response, respBody []byte, started time.Time, latency time.Duration) {
+	entry := harEntry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Time:            float64(latency) / float64(time.Millisecond),
+		Request: harRequest{
+			Method:      req.Method,
+			URL:         req.URL.String(),
+			HTTPVersion: req.Proto,
+			Headers:     harHeaders(redactHeaders(req.Header)),
+			QueryString: []harNameValue{},
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Response: harResponse{
+			Headers:     []harNameValue{},
+			Content:     harContent{MimeType: "x-unknown"},
+			HeadersSize: -1,
+			BodySize:    -1,
+		},
+		Cache:   struct{}{},
+		Timings: harTimings{Send: 0, Wait: float64(latency) / float64(time.Millisecond), Receive: 0},
+	}
+
+	for name, values := range req.URL.Query() {
+		for _, value := range values {
+			entry.Request.QueryString = append(entry.Request.QueryString, harNameValue{Name: name, Value: value})
+		}
+	}
+	if len(reqBody) > 0 {
+		entry.Request.PostData = &harPostData{MimeType: req.Header.Get("Content-Type"), Text: string(reqBody)}
+	}
+
+	if resp != nil {
+		entry.Response.Status = resp.StatusCode
+		entry.Response.StatusText = http.StatusText(resp.StatusCode)
+		entry.Response.HTTPVersion = resp.Proto
+		entry.Response.Headers = harHeaders(resp.Header)
+		entry.Response.Content = harContent{
+			Size:     len(respBody),
+			MimeType: resp.Header.Get("Content-Type"),
+			Text:     string(respBody),
+		}
+		entry.Response.BodySize = len(respBody)
+	}
+
+	h.mu.Lock()
+	h.entries = append(h.entries, entry)
+	h.mu.Unlock()
+}
+
+// harHeaders converts header to HAR name/value pairs sorted by name
+func harHeaders(header http.Header) []harNameValue {
+	pairs := []harNameValue{}
+	for name, values := range header {
+		for _, value := range values {
+			pairs = append(pairs, harNameValue{Name: name, Value: value})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].Name < pairs[j].Name })
+	return pairs
+}
+
+// The types below model the parts of the HAR 1.2 format that are recorded,
+// see http://www.softwareishard.com/blog/har-12-spec/
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
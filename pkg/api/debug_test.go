@@ -0,0 +1,107 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDebugLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), `"value":"192.0.2.1"`) {
+			t.Errorf("Expected the request body to reach the server, got %s", body)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"record":{"id":"record1","type":"A","name":"www","value":"192.0.2.1","zone_id":"zone1"}}`))
+	}))
+	defer server.Close()
+
+	var log bytes.Buffer
+	client := NewClient("secret-token", WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithDebugLog(&log))
+
+	record, err := client.CreateRecord(Record{Type: "A", Name: "www", Value: "192.0.2.1", ZoneID: "zone1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if record.ID != "record1" {
+		t.Errorf("Expected the response body to reach the client, got %+v", record)
+	}
+
+	output := log.String()
+	for _, want := range []string{"--> POST " + server.URL + "/records", "<-- 200 OK", `"value":"192.0.2.1"`, `"id":"record1"`, "Auth-Api-Token: [REDACTED]"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected %q in debug log, got:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "secret-token") {
+		t.Errorf("Expected the API token to be redacted, got:\n%s", output)
+	}
+}
+
+func TestDebugTransportLeavesRequestUnchanged(t *testing.T) {
+	var sent string
+	transport := &debugTransport{
+		base: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			body, _ := io.ReadAll(req.Body)
+			sent = string(body)
+			return &http.Response{StatusCode: http.StatusOK, Status: "200 OK", Header: http.Header{}, Body: http.NoBody, Request: req}, nil
+		}),
+		log: io.Discard,
+	}
+
+	// A body without GetBody has to be read to be logged
+	body := io.NopCloser(strings.NewReader(`{"name":"example.com"}`))
+	req, _ := http.NewRequest(http.MethodPost, "https://dns.example/api/v1/zones", nil)
+	req.Body = body
+
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sent != `{"name":"example.com"}` {
+		t.Errorf("Expected the body to be sent, got %q", sent)
+	}
+	if req.Body != body || req.GetBody != nil || resp.Request != req {
+		t.Error("Expected the request of the caller to be left unchanged")
+	}
+}
+
+func TestHARRecorder(t *testing.T) {
+	server := setupTestServer(t, "/zones", http.StatusOK, ZonesResponse{Zones: []Zone{{ID: "zone1", Name: "example.com"}}})
+	defer server.Close()
+
+	har := NewHARRecorder("hetznerdns-test")
+	client := NewClient("test-token", WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithHARRecorder(har))
+
+	if _, err := client.GetZonesPage(1, 10); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if har.Len() != 1 {
+		t.Fatalf("Expected 1 recorded request, got %d", har.Len())
+	}
+
+	var buf bytes.Buffer
+	if _, err := har.WriteTo(&buf); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if strings.Contains(buf.String(), "test-token") {
+		t.Error("Expected the API token to be redacted in the HAR document")
+	}
+
+	var doc harDocument
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Expected a valid HAR document, got %v", err)
+	}
+	entry := doc.Log.Entries[0]
+	if doc.Log.Version != "1.2" || entry.Request.Method != "GET" || entry.Response.Status != http.StatusOK {
+		t.Errorf("Unexpected HAR entry %+v", entry)
+	}
+	if !strings.Contains(entry.Response.Content.Text, "example.com") || len(entry.Request.QueryString) != 2 {
+		t.Errorf("Expected response body and query string to be recorded, got %+v", entry)
+	}
+}
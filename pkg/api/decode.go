@@ -0,0 +1,153 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// errMissingField is the DecodeError cause when a response lacks a required field
+var errMissingField = errors.New("field is missing")
+
+// DecodeError is returned when a response body does not have the expected format
+type DecodeError struct {
+	// Path is the path of the offending field as reported by encoding/json,
+	// e.g. "meta.pagination.page", or "ttl" for a field of a zone. It is empty
+	// if the body is not valid JSON.
+	Path string
+	// Err is the underlying error
+	Err error
+}
+
+// Error implements the error interface
+func (e *DecodeError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("error decoding response: %v", e.Err)
+	}
+	return fmt.Sprintf("error decoding response field %s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// decodeJSON decodes a response body into out, which must be a pointer.
+// Failures are reported as *DecodeError with the path of the offending field.
+func decodeJSON(body []byte, out interface{}) error {
+	err := json.Unmarshal(body, out)
+	if err == nil {
+		return nil
+	}
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		return &DecodeError{
+			Path: strings.TrimPrefix(typeErr.Field, "."),
+			Err:  fmt.Errorf("got %s, want %s", typeErr.Value, typeErr.Type),
+		}
+	}
+	return &DecodeError{Err: err}
+}
+
+// decodeTimestamp parses the value of the timestamp field name, accepting
+// every format the API uses
+func decodeTimestamp(name, value string) (time.Time, error) {
+	t, err := parseTimestamp(value)
+	if err != nil {
+		return time.Time{}, &json.UnmarshalTypeError{Value: "string " + strconv.Quote(value), Type: timeType, Field: name}
+	}
+	return t, nil
+}
+
+// unknownFields returns the members of the JSON object data that have no
+// field in the struct type t, or nil if there are none
+func unknownFields(data []byte, t reflect.Type) (map[string]json.RawMessage, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+
+	known := structFields(t)
+	var extra map[string]json.RawMessage
+	for key, value := range members {
+		if known[strings.ToLower(key)] {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[key] = value
+	}
+	return extra, nil
+}
+
+// fieldNamesCache maps struct types to the lower case JSON keys of their fields
+var fieldNamesCache sync.Map
+
+// structFields returns the lower case JSON keys of the fields of the struct
+// type t, which encoding/json matches ignoring case
+func structFields(t reflect.Type) map[string]bool {
+	if cached, ok := fieldNamesCache.Load(t); ok {
+		return cached.(map[string]bool)
+	}
+
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = field.Name
+		}
+		names[strings.ToLower(name)] = true
+	}
+
+	fieldNamesCache.Store(t, names)
+	return names
+}
+
+// marshalWithExtra encodes v as a JSON object and appends the members of
+// extra that v does not already contain, in key order
+func marshalWithExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	fields := structFields(reflect.TypeOf(v))
+	keys := make([]string, 0, len(extra))
+	for key := range extra {
+		if !fields[strings.ToLower(key)] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(bytes.TrimSuffix(data, []byte("}")))
+	for _, key := range keys {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		keyJSON, _ := json.Marshal(key)
+		buf.Write(keyJSON)
+		buf.WriteByte(':')
+		buf.Write(extra[key])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,146 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newStaticServer creates a test server answering every request with body
+func newStaticServer(body string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+}
+
+func TestDecodeKeepsUnknownFields(t *testing.T) {
+	server := newStaticServer(`{"zones":[{"id":"zone1","name":"example.com","ttl":86400,
+		"created":"2018-08-23 13:21:39.489 +0000 UTC","dnssec":{"enabled":true}}]}`)
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	zones, err := client.GetZones()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	zone := zones[0]
+	if zone.TTL != 86400 || zone.Created.IsZero() {
+		t.Errorf("Known fields were not decoded: %+v", zone)
+	}
+	if len(zone.Extra) != 1 || string(zone.Extra["dnssec"]) != `{"enabled":true}` {
+		t.Errorf("Expected the unknown field in Extra, got %v", zone.Extra)
+	}
+
+	// Encoding the zone again keeps the unknown field
+	data, err := json.Marshal(zone)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !strings.Contains(string(data), `"dnssec":{"enabled":true}`) || !strings.Contains(string(data), `"name":"example.com"`) {
+		t.Errorf("Expected encoded zone to include all fields, got %s", data)
+	}
+}
+
+func TestDecodeRecordTimestamps(t *testing.T) {
+	server := newStaticServer(`{"records":[{"id":"record1","type":"A","name":"www","value":"192.0.2.1","zone_id":"zone1",
+		"created":"2024-01-02 03:04:05.123 +0000 UTC","modified":"2024-02-03 04:05:06.456 +0000 UTC"}]}`)
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	records, err := client.GetRecords("zone1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	created := time.Date(2024, 1, 2, 3, 4, 5, 123000000, time.UTC)
+	modified := time.Date(2024, 2, 3, 4, 5, 6, 456000000, time.UTC)
+	if !records[0].Created.Equal(created) || !records[0].Modified.Equal(modified) || records[0].Extra != nil {
+		t.Errorf("Expected timestamps and no extra fields, got %+v", records[0])
+	}
+}
+
+func TestDecodeEmbeddedStructs(t *testing.T) {
+	type named struct {
+		Name string `json:"name"`
+	}
+	var out struct {
+		named
+		Record Record `json:"record"`
+	}
+	body := `{"name":"example","record":{"id":"record1","created":"2024-01-02 03:04:05.123 +0000 UTC","weight":5}}`
+	if err := decodeJSON([]byte(body), &out); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if out.Name != "example" || out.Record.ID != "record1" || out.Record.Created.IsZero() {
+		t.Errorf("Expected all fields to be decoded, got %+v", out)
+	}
+	if len(out.Record.Extra) != 1 || string(out.Record.Extra["weight"]) != "5" {
+		t.Errorf("Expected the unknown field in Extra, got %v", out.Record.Extra)
+	}
+}
+
+func TestDecodeErrorPath(t *testing.T) {
+	// Fields of zones are reported from the zone on, older Go versions
+	// prefix the path with "zones."
+	tests := []struct {
+		body string
+		path string
+		want string
+	}{
+		{`{"zones":[{"id":"zone1","ttl":86400},{"id":"zone2","ttl":"one day"}]}`, "ttl", "got string"},
+		{`{"zones":[{"id":"zone1","ttl":  "one day"}]}`, "ttl", "got string"},
+		{`{"zones":[{"id":"zone1","created":"yesterday"}]}`, "created", `got string "yesterday", want time.Time`},
+		{`{"zones":[{"id":"zone1","txt_verification":{"name":1}}]}`, "txt_verification.name", "got number"},
+		{`{"zones":{"id":"zone1"}}`, "zones", "got object"},
+		{`{"meta":{"pagination":{"page":"1"}}}`, "meta.pagination.page", "got string"},
+	}
+
+	for _, tt := range tests {
+		server := newStaticServer(tt.body)
+		client := newTestClient(server)
+
+		_, err := client.GetZonesPage(1, 0)
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) {
+			t.Errorf("%s: expected *DecodeError, got %v", tt.body, err)
+		} else if decodeErr.Path != tt.path && decodeErr.Path != "zones."+tt.path {
+			t.Errorf("%s: expected path %q, got %q (%v)", tt.body, tt.path, decodeErr.Path, err)
+		} else if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected %q in the error, got %v", tt.body, tt.want, err)
+		}
+
+		server.Close()
+	}
+}
+
+func TestDecodeErrorMissingObject(t *testing.T) {
+	server := newStaticServer(`{"message":"ok"}`)
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	_, err := client.GetRecord("record1")
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) || decodeErr.Path != "record" {
+		t.Errorf("Expected *DecodeError for the record field, got %v", err)
+	}
+}
+
+func TestDecodeErrorInvalidJSON(t *testing.T) {
+	server := newStaticServer(`{"zone":`)
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	_, err := client.GetZone("zone1")
+	var decodeErr *DecodeError
+	if !errors.As(err, &decodeErr) || decodeErr.Path != "" {
+		t.Errorf("Expected *DecodeError without a path, got %v", err)
+	}
+}
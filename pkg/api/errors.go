@@ -0,0 +1,111 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors that an *APIError matches with errors.Is, depending on the
+// HTTP status code of the response
+var (
+	// ErrUnauthorized is matched by 401 responses, usually caused by a missing or invalid API token
+	ErrUnauthorized = errors.New("unauthorized")
+
+	// ErrForbidden is matched by 403 responses
+	ErrForbidden = errors.New("forbidden")
+
+	// ErrNotFound is matched by 404 responses and returned when a lookup finds nothing
+	ErrNotFound = errors.New("not found")
+
+	// ErrValidation is matched by 400 and 422 responses where the API rejected the request data
+	ErrValidation = errors.New("validation failed")
+
+	// ErrRateLimited is matched by 429 responses
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrServer is matched by 5xx responses
+	ErrServer = errors.New("server error")
+)
+
+// APIError is returned when the Hetzner DNS API answers with an unexpected status code
+type APIError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+	// Code is the error code reported in the response body, if any
+	Code string
+	// Message is the error message reported in the response body, if any
+	Message string
+	// Method is the HTTP method of the failed request
+	Method string
+	// Path is the URL path and query of the failed request
+	Path string
+	// Body is the raw response body
+	Body []byte
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = strings.TrimSpace(string(e.Body))
+	}
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	return fmt.Sprintf("API error: %s %s: %s (status code: %d)", e.Method, e.Path, msg, e.StatusCode)
+}
+
+// Is reports whether the error matches one of the sentinel errors of this package
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrValidation:
+		return e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.StatusCode >= 500
+	}
+	return false
+}
+
+// newAPIError builds an APIError from a failed response and its already read body
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+	}
+	if resp.Request != nil {
+		apiErr.Method = resp.Request.Method
+		apiErr.Path = resp.Request.URL.RequestURI()
+	}
+
+	// The API reports errors either as {"error": {"message": ..., "code": ...}}
+	// or as a top level {"message": ...}
+	var result struct {
+		Error struct {
+			Message string      `json:"message"`
+			Code    interface{} `json:"code"`
+		} `json:"error"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &result); err == nil {
+		apiErr.Message = result.Error.Message
+		if apiErr.Message == "" {
+			apiErr.Message = result.Message
+		}
+		if result.Error.Code != nil {
+			apiErr.Code = fmt.Sprint(result.Error.Code)
+		}
+	}
+
+	return apiErr
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIErrorStatusCodes(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		sentinel   error
+	}{
+		{http.StatusUnauthorized, ErrUnauthorized},
+		{http.StatusForbidden, ErrForbidden},
+		{http.StatusNotFound, ErrNotFound},
+		{http.StatusBadRequest, ErrValidation},
+		{http.StatusUnprocessableEntity, ErrValidation},
+		{http.StatusTooManyRequests, ErrRateLimited},
+		{http.StatusServiceUnavailable, ErrServer},
+	}
+
+	for _, tt := range tests {
+		server := setupTestServer(t, "/records/record1", tt.statusCode, map[string]interface{}{
+			"error": map[string]interface{}{"message": "something went wrong", "code": tt.statusCode},
+		})
+		client := newTestClient(server)
+
+		err := client.DeleteRecord("record1")
+		server.Close()
+
+		if !errors.Is(err, tt.sentinel) {
+			t.Errorf("Status %d: expected errors.Is(err, %v), got %v", tt.statusCode, tt.sentinel, err)
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Status %d: expected *APIError, got %T", tt.statusCode, err)
+		}
+		if apiErr.StatusCode != tt.statusCode {
+			t.Errorf("Expected status code %d, got %d", tt.statusCode, apiErr.StatusCode)
+		}
+		if apiErr.Message != "something went wrong" {
+			t.Errorf("Expected message 'something went wrong', got '%s'", apiErr.Message)
+		}
+		if apiErr.Method != "DELETE" || apiErr.Path != "/records/record1" {
+			t.Errorf("Expected request DELETE /records/record1, got %s %s", apiErr.Method, apiErr.Path)
+		}
+	}
+}
+
+func TestAPIErrorBodyFormats(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"message":"invalid value for type A"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	_, err := client.CreateRecord(Record{Type: "A", Name: "www", Value: "nope", ZoneID: "zone1"})
+
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("Expected *APIError, got %T", err)
+	}
+	if apiErr.Message != "invalid value for type A" {
+		t.Errorf("Expected top level message to be decoded, got '%s'", apiErr.Message)
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Error("Did not expect a validation error to match ErrNotFound")
+	}
+
+	// Non-JSON bodies are kept verbatim and used in the message
+	plain := &APIError{StatusCode: http.StatusBadGateway, Method: "GET", Path: "/zones", Body: []byte("bad gateway\n")}
+	if !strings.Contains(plain.Error(), "bad gateway") || !strings.Contains(plain.Error(), "502") {
+		t.Errorf("Unexpected error string: %s", plain.Error())
+	}
+}
+
+func TestGetZoneIDByNameNotFound(t *testing.T) {
+	server := setupTestServer(t, "/zones", http.StatusOK, ZonesResponse{Zones: []Zone{{ID: "zone1", Name: "example.com"}}})
+	defer server.Close()
+
+	client := newTestClient(server)
+	_, err := client.GetZoneIDByName("nonexistent.com")
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,344 @@
+package api
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RecordFilter selects records by their fields. Fields left empty match every
+// record, a record has to match all fields that are set.
+//
+// Name and Value are matched case-insensitively. A pattern enclosed in
+// slashes, like "/^_dmarc/", is a regular expression. Otherwise Name is
+// matched exactly, or as a glob if it contains *, ? or [, e.g. "_dmarc*".
+// Value matches values containing it as a whole, not as part of a longer name
+// or address, so "192.0.2.1" does not match "192.0.2.10". An IP address also
+// matches values that are the same address, and a CIDR like "10.0.0.0/8"
+// matches the IP addresses in the network.
+type RecordFilter struct {
+	// Types are the record types to select, e.g. "A" and "AAAA"
+	Types []string
+	// Name is an exact name, glob or regular expression
+	Name string
+	// Value is a text, IP address, CIDR or regular expression
+	Value string
+	// TTL is a TTL in seconds, optionally preceded by a comparison like
+	// "<3600" or ">=300". "default" selects records using the zone TTL.
+	TTL string
+	// ModifiedSince selects records modified at or after this time. Records
+	// that were never modified count as modified when they were created.
+	ModifiedSince time.Time
+}
+
+// RecordMatcher is a compiled RecordFilter
+type RecordMatcher struct {
+	filter RecordFilter
+	types  map[string]bool
+	name   func(string) bool
+	value  func(string) bool
+	ttl    func(int) bool
+}
+
+// NewRecordMatcher compiles filter, returning an error if one of its patterns
+// is invalid
+func NewRecordMatcher(filter RecordFilter) (*RecordMatcher, error) {
+	m := &RecordMatcher{filter: filter}
+
+	for _, t := range filter.Types {
+		if m.types == nil {
+			m.types = make(map[string]bool)
+		}
+		m.types[strings.ToUpper(strings.TrimSpace(t))] = true
+	}
+
+	var err error
+	if filter.Name != "" {
+		if m.name, err = compileNamePattern(filter.Name); err != nil {
+			return nil, err
+		}
+	}
+	if filter.Value != "" {
+		if m.value, err = compileValuePattern(filter.Value); err != nil {
+			return nil, err
+		}
+	}
+	if filter.TTL != "" {
+		if m.ttl, err = compileTTLCondition(filter.TTL); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// Match reports whether record matches the filter
+func (m *RecordMatcher) Match(record Record) bool {
+	if m.types != nil && !m.types[strings.ToUpper(record.Type)] {
+		return false
+	}
+	if m.name != nil && !m.name(record.Name) {
+		return false
+	}
+	if m.value != nil && !m.value(record.Value) {
+		return false
+	}
+	if m.ttl != nil && !m.ttl(record.TTL) {
+		return false
+	}
+	if !m.filter.ModifiedSince.IsZero() {
+		modified := record.Modified
+		if modified.IsZero() {
+			modified = record.Created
+		}
+		if modified.Before(m.filter.ModifiedSince) {
+			return false
+		}
+	}
+	return true
+}
+
+// Filter returns the records matching the filter, in their original order
+func (m *RecordMatcher) Filter(records []Record) []Record {
+	var filtered []Record
+	for _, record := range records {
+		if m.Match(record) {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered
+}
+
+// FilterRecords returns the records matching filter
+func FilterRecords(records []Record, filter RecordFilter) ([]Record, error) {
+	m, err := NewRecordMatcher(filter)
+	if err != nil {
+		return nil, err
+	}
+	return m.Filter(records), nil
+}
+
+// regexpPattern returns the regular expression of a pattern enclosed in
+// slashes, or nil if pattern is not enclosed in slashes
+func regexpPattern(field, pattern string) (*regexp.Regexp, error) {
+	if len(pattern) < 2 || !strings.HasPrefix(pattern, "/") || !strings.HasSuffix(pattern, "/") {
+		return nil, nil
+	}
+	re, err := regexp.Compile("(?i)" + pattern[1:len(pattern)-1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s regular expression %s: %w", field, pattern, err)
+	}
+	return re, nil
+}
+
+func compileNamePattern(pattern string) (func(string) bool, error) {
+	re, err := regexpPattern("name", pattern)
+	if err != nil {
+		return nil, err
+	}
+	if re != nil {
+		return re.MatchString, nil
+	}
+
+	pattern = strings.ToLower(pattern)
+	if strings.ContainsAny(pattern, "*?[") {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid name pattern %q: %w", pattern, err)
+		}
+		return func(name string) bool {
+			ok, _ := path.Match(pattern, strings.ToLower(name))
+			return ok
+		}, nil
+	}
+	return func(name string) bool {
+		return strings.ToLower(name) == pattern
+	}, nil
+}
+
+func compileValuePattern(pattern string) (func(string) bool, error) {
+	re, err := regexpPattern("value", pattern)
+	if err != nil {
+		return nil, err
+	}
+	if re != nil {
+		return re.MatchString, nil
+	}
+
+	if _, network, err := net.ParseCIDR(pattern); err == nil {
+		return func(value string) bool {
+			ip := net.ParseIP(strings.TrimSpace(value))
+			return ip != nil && network.Contains(ip)
+		}, nil
+	}
+
+	// An IP address matches the same address written differently, like
+	// 2001:db8:0::1 for 2001:db8::1, and otherwise only as a whole, so that
+	// 192.0.2.1 matches "ip4:192.0.2.1" but not 192.0.2.10
+	if ip := net.ParseIP(pattern); ip != nil {
+		return func(value string) bool {
+			if other := net.ParseIP(strings.TrimSpace(value)); other != nil {
+				return ip.Equal(other)
+			}
+			return indexWhole(value, pattern, 0) >= 0
+		}, nil
+	}
+
+	return func(value string) bool {
+		return indexWhole(value, pattern, 0) >= 0
+	}, nil
+}
+
+// ReplaceWhole replaces the occurrences of old in s that are not part of a
+// longer name, number or address with replacement, ignoring case, and returns
+// the result and the number of replacements. "192.0.2.1" is not replaced in
+// "192.0.2.10", nor is "example.com" in "mail.example.com", but they are
+// replaced in "ip4:192.0.2.1", "example.com." and "10 example.com". These are
+// the occurrences a RecordFilter Value matches.
+func ReplaceWhole(s, old, replacement string) (string, int) {
+	var b strings.Builder
+	n := 0
+	last := 0
+	for i := indexWhole(s, old, 0); i >= 0; i = indexWhole(s, old, last) {
+		b.WriteString(s[last:i])
+		b.WriteString(replacement)
+		last = i + len(old)
+		n++
+	}
+	if n == 0 {
+		return s, 0
+	}
+	b.WriteString(s[last:])
+	return b.String(), n
+}
+
+// indexWhole returns the index of the first occurrence of substr in s at or
+// after from that is not part of a longer token, ignoring case, or -1 if
+// there is none
+func indexWhole(s, substr string, from int) int {
+	if substr == "" {
+		return -1
+	}
+	for i := from; i+len(substr) <= len(s); i++ {
+		if strings.EqualFold(s[i:i+len(substr)], substr) && wholeAt(s, i, i+len(substr)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// wholeAt reports whether s[start:end] is not part of a longer token
+func wholeAt(s string, start, end int) bool {
+	if start > 0 {
+		// A dot before makes it the end of a longer name
+		if c := s[start-1]; isTokenChar(c) || c == '.' {
+			return false
+		}
+	}
+	if end < len(s) {
+		c := s[end]
+		if isTokenChar(c) {
+			return false
+		}
+		// A trailing dot ends a fully qualified name, but a dot or colon
+		// followed by more characters continues the name or address
+		if (c == '.' || c == ':') && end+1 < len(s) && isTokenChar(s[end+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isTokenChar reports whether c can be part of a host name or address
+func isTokenChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_'
+}
+
+func compileTTLCondition(condition string) (func(int) bool, error) {
+	condition = strings.TrimSpace(condition)
+	if strings.EqualFold(condition, "default") {
+		return func(ttl int) bool { return ttl == 0 }, nil
+	}
+
+	// Longer operators first, so that "<=" is not taken for "<"
+	operators := []struct {
+		op      string
+		compare func(a, b int) bool
+	}{
+		{"<=", func(a, b int) bool { return a <= b }},
+		{">=", func(a, b int) bool { return a >= b }},
+		{"<", func(a, b int) bool { return a < b }},
+		{">", func(a, b int) bool { return a > b }},
+		{"=", func(a, b int) bool { return a == b }},
+	}
+	compare := func(a, b int) bool { return a == b }
+	for _, o := range operators {
+		if strings.HasPrefix(condition, o.op) {
+			compare = o.compare
+			condition = strings.TrimSpace(condition[len(o.op):])
+			break
+		}
+	}
+
+	want, err := strconv.Atoi(condition)
+	if err != nil || want < 0 {
+		return nil, fmt.Errorf("invalid TTL %q, expected seconds like 3600, a comparison like <3600 or default", condition)
+	}
+	return func(ttl int) bool { return compare(ttl, want) }, nil
+}
+
+// RecordSortKeys are the keys SortRecords can sort by
+var RecordSortKeys = []string{"name", "type", "value", "ttl", "created", "modified"}
+
+// SortRecords sorts records by a comma-separated list of keys from
+// RecordSortKeys, e.g. "type,name". A key preceded by "-" sorts in descending
+// order. Records that compare equal keep their order.
+func SortRecords(records []Record, by string) error {
+	type sortKey struct {
+		compare func(a, b Record) int
+		desc    bool
+	}
+
+	var keys []sortKey
+	for _, key := range strings.Split(by, ",") {
+		key = strings.ToLower(strings.TrimSpace(key))
+		desc := strings.HasPrefix(key, "-")
+		key = strings.TrimPrefix(key, "-")
+
+		var compare func(a, b Record) int
+		switch key {
+		case "name":
+			compare = func(a, b Record) int { return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)) }
+		case "type":
+			compare = func(a, b Record) int { return strings.Compare(a.Type, b.Type) }
+		case "value":
+			compare = func(a, b Record) int { return strings.Compare(a.Value, b.Value) }
+		case "ttl":
+			compare = func(a, b Record) int { return a.TTL - b.TTL }
+		case "created":
+			compare = func(a, b Record) int { return a.Created.Compare(b.Created) }
+		case "modified":
+			compare = func(a, b Record) int { return a.Modified.Compare(b.Modified) }
+		default:
+			return fmt.Errorf("invalid sort key %q, expected one of %s", key, strings.Join(RecordSortKeys, ", "))
+		}
+		keys = append(keys, sortKey{compare: compare, desc: desc})
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		for _, key := range keys {
+			c := key.compare(records[i], records[j])
+			if key.desc {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	return nil
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var filterTestRecords = []Record{
+	{ID: "1", Name: "@", Type: "A", Value: "10.1.2.3", TTL: 300},
+	{ID: "2", Name: "www", Type: "A", Value: "203.0.113.10"},
+	{ID: "3", Name: "www", Type: "AAAA", Value: "2001:db8::10", TTL: 3600},
+	{ID: "4", Name: "_dmarc", Type: "TXT", Value: `"v=DMARC1; p=reject"`, TTL: 3600},
+	{ID: "5", Name: "_dmarc.shop", Type: "TXT", Value: `"v=DMARC1; p=none"`},
+	{ID: "6", Name: "mail", Type: "MX", Value: "10 mail.example.com", TTL: 86400},
+}
+
+func recordIDs(records []Record) string {
+	var ids []string
+	for _, record := range records {
+		ids = append(ids, record.ID)
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestFilterRecords(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter RecordFilter
+		want   string
+	}{
+		{"no filter", RecordFilter{}, "1,2,3,4,5,6"},
+		{"types", RecordFilter{Types: []string{"a", "AAAA"}}, "1,2,3"},
+		{"exact name", RecordFilter{Name: "WWW"}, "2,3"},
+		{"glob name", RecordFilter{Name: "_dmarc*"}, "4,5"},
+		{"regexp name", RecordFilter{Name: `/^_dmarc\./`}, "5"},
+		{"type and name", RecordFilter{Types: []string{"TXT"}, Name: "_dmarc"}, "4"},
+		{"value text", RecordFilter{Value: "dmarc1"}, "4,5"},
+		{"value regexp", RecordFilter{Value: "/p=(reject|quarantine)/"}, "4"},
+		{"value CIDR", RecordFilter{Value: "10.0.0.0/8"}, "1"},
+		{"value IPv6 CIDR", RecordFilter{Value: "2001:db8::/32"}, "3"},
+		{"exact TTL", RecordFilter{TTL: "3600"}, "3,4"},
+		{"TTL comparison", RecordFilter{TTL: ">= 3600"}, "3,4,6"},
+		{"TTL below", RecordFilter{TTL: "<3600"}, "1,2,5"},
+		{"default TTL", RecordFilter{TTL: "default"}, "2,5"},
+	}
+
+	for _, tt := range tests {
+		records, err := FilterRecords(filterTestRecords, tt.filter)
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.name, err)
+		}
+		if got := recordIDs(records); got != tt.want {
+			t.Errorf("%s: expected records %s, got %s", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestFilterRecordsWholeValue(t *testing.T) {
+	records := []Record{
+		{ID: "exact", Type: "A", Value: "203.0.113.10"},
+		{ID: "longer", Type: "A", Value: "203.0.113.100"},
+		{ID: "spf", Type: "TXT", Value: `"v=spf1 ip4:203.0.113.10 -all"`},
+		{ID: "spf-longer", Type: "TXT", Value: `"v=spf1 ip4:203.0.113.101 -all"`},
+		{ID: "ipv6", Type: "AAAA", Value: "2001:db8:0::10"},
+		{ID: "host", Type: "CNAME", Value: "mail.example.com."},
+		{ID: "subdomain", Type: "CNAME", Value: "smtp.mail.example.com."},
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"203.0.113.10", "exact,spf"},
+		{"2001:db8::10", "ipv6"},
+		{"MAIL.example.com", "host"},
+		{"example", ""},
+	}
+
+	for _, tt := range tests {
+		filtered, err := FilterRecords(records, RecordFilter{Value: tt.value})
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", tt.value, err)
+		}
+		if got := recordIDs(filtered); got != tt.want {
+			t.Errorf("%s: expected records %s, got %s", tt.value, tt.want, got)
+		}
+	}
+}
+
+func TestFilterRecordsModifiedSince(t *testing.T) {
+	since := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	records := []Record{
+		{ID: "old", Created: since.Add(-time.Hour)},
+		{ID: "created", Created: since.Add(time.Hour)},
+		{ID: "modified", Created: since.Add(-time.Hour), Modified: since},
+	}
+
+	filtered, err := FilterRecords(records, RecordFilter{ModifiedSince: since})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got := recordIDs(filtered); got != "created,modified" {
+		t.Errorf("Expected records created,modified, got %s", got)
+	}
+}
+
+func TestFilterRecordsErrors(t *testing.T) {
+	for _, filter := range []RecordFilter{
+		{Name: "/(/"},
+		{Name: "[a"},
+		{Value: "/[/"},
+		{TTL: "one hour"},
+		{TTL: "<"},
+		{TTL: "-5"},
+	} {
+		if _, err := FilterRecords(filterTestRecords, filter); err == nil {
+			t.Errorf("Expected an error for %+v", filter)
+		}
+	}
+}
+
+func TestSortRecords(t *testing.T) {
+	tests := map[string]string{
+		"name":       "1,4,5,6,2,3",
+		"type,name":  "1,2,3,6,4,5",
+		"-ttl":       "6,3,4,1,2,5",
+		"ttl, -name": "2,5,1,3,4,6",
+	}
+
+	for by, want := range tests {
+		records := append([]Record(nil), filterTestRecords...)
+		if err := SortRecords(records, by); err != nil {
+			t.Fatalf("%s: expected no error, got %v", by, err)
+		}
+		if got := recordIDs(records); got != want {
+			t.Errorf("%s: expected records %s, got %s", by, want, got)
+		}
+	}
+
+	if err := SortRecords(filterTestRecords, "priority"); err == nil {
+		t.Error("Expected an error for an unknown sort key")
+	}
+}
+
+func TestReplaceWhole(t *testing.T) {
+	tests := []struct {
+		s, old, replacement string
+		want                string
+		n                   int
+	}{
+		{"192.0.2.1", "192.0.2.1", "198.51.100.7", "198.51.100.7", 1},
+		{"192.0.2.10", "192.0.2.1", "198.51.100.7", "192.0.2.10", 0},
+		{"10.192.0.2.1", "192.0.2.1", "198.51.100.7", "10.192.0.2.1", 0},
+		{`"v=spf1 ip4:192.0.2.1 ip4:192.0.2.1/32 ip4:192.0.2.100 -all"`, "192.0.2.1", "198.51.100.7", `"v=spf1 ip4:198.51.100.7 ip4:198.51.100.7/32 ip4:192.0.2.100 -all"`, 2},
+		{"2001:db8::1", "2001:db8::1", "2001:db8::2", "2001:db8::2", 1},
+		{"2001:db8::1:5", "2001:db8::1", "2001:db8::2", "2001:db8::1:5", 0},
+		{"Old.Example.com.", "old.example.com", "new.example.com", "new.example.com.", 1},
+		{"10 old.example.com", "old.example.com", "new.example.com", "10 new.example.com", 1},
+		{"smtp.old.example.com", "old.example.com", "new.example.com", "smtp.old.example.com", 0},
+		{"old.example.com.au", "old.example.com", "new.example.com", "old.example.com.au", 0},
+		{"old-old.example.com", "old.example.com", "new.example.com", "old-old.example.com", 0},
+		{"a a", "a", "", " ", 2},
+	}
+
+	for _, tt := range tests {
+		got, n := ReplaceWhole(tt.s, tt.old, tt.replacement)
+		if got != tt.want || n != tt.n {
+			t.Errorf("ReplaceWhole(%q, %q): expected %q (%d), got %q (%d)", tt.s, tt.old, tt.want, tt.n, got, n)
+		}
+	}
+}
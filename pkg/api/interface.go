@@ -0,0 +1,41 @@
+package api
+
+import "context"
+
+// DNSClient is the set of Hetzner DNS API operations provided by Client.
+// Code that depends on DNSClient instead of *Client can be tested against a
+// stand-in. It covers the context-aware methods; the methods without a
+// context are convenience wrappers of these.
+type DNSClient interface {
+	// Zones
+	GetZonesContext(ctx context.Context) ([]Zone, error)
+	GetZonesPageContext(ctx context.Context, page, perPage int) (*ZonesResponse, error)
+	GetZoneIDByNameContext(ctx context.Context, name string) (string, error)
+	GetZoneByNameContext(ctx context.Context, name string) (*Zone, error)
+	GetZoneContext(ctx context.Context, zoneID string) (*Zone, error)
+	CreateZoneContext(ctx context.Context, name string, ttl int) (*Zone, error)
+	UpdateZoneContext(ctx context.Context, zone Zone) (*Zone, error)
+	DeleteZoneContext(ctx context.Context, zoneID string) error
+	ExportZoneFileContext(ctx context.Context, zoneID string) (string, error)
+	ValidateZoneFileContext(ctx context.Context, zoneFile string) (*ZoneFileValidation, error)
+	ImportZoneFileContext(ctx context.Context, zoneID, zoneFile string) (*Zone, error)
+
+	// Records
+	GetRecordsContext(ctx context.Context, zoneID string) ([]Record, error)
+	GetRecordsPageContext(ctx context.Context, zoneID string, page, perPage int) (*RecordsResponse, error)
+	GetRecordContext(ctx context.Context, recordID string) (*Record, error)
+	CreateRecordContext(ctx context.Context, record Record) (*Record, error)
+	UpdateRecordContext(ctx context.Context, record Record) (*Record, error)
+	CreateRecordsContext(ctx context.Context, records []Record) (*BulkCreateResult, error)
+	UpdateRecordsContext(ctx context.Context, records []Record) (*BulkUpdateResult, error)
+	DeleteRecordContext(ctx context.Context, recordID string) error
+
+	// Primary servers of secondary zones
+	GetPrimaryServersContext(ctx context.Context, zoneID string) ([]PrimaryServer, error)
+	GetPrimaryServerContext(ctx context.Context, id string) (*PrimaryServer, error)
+	CreatePrimaryServerContext(ctx context.Context, server PrimaryServer) (*PrimaryServer, error)
+	UpdatePrimaryServerContext(ctx context.Context, server PrimaryServer) (*PrimaryServer, error)
+	DeletePrimaryServerContext(ctx context.Context, id string) error
+}
+
+var _ DNSClient = (*Client)(nil)
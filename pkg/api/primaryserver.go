@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"time"
+)
+
+// PrimaryServer is a primary name server a secondary zone is transferred from
+type PrimaryServer struct {
+	ID       string    `json:"id,omitempty"`
+	Address  string    `json:"address"`
+	Port     int       `json:"port"`
+	ZoneID   string    `json:"zone_id"`
+	Created  time.Time `json:"created,omitzero"`
+	Modified time.Time `json:"modified,omitzero"`
+	// Extra holds the fields of the API response this version does not know
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// MarshalJSON implements json.Marshaler, including the fields kept in Extra
+func (p PrimaryServer) MarshalJSON() ([]byte, error) {
+	type plain PrimaryServer
+	return marshalWithExtra(plain(p), p.Extra)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, accepting the timestamp formats
+// of the API and keeping unknown fields in Extra
+func (p *PrimaryServer) UnmarshalJSON(data []byte) error {
+	type plain PrimaryServer
+	aux := struct {
+		*plain
+		Created  string `json:"created"`
+		Modified string `json:"modified"`
+	}{plain: (*plain)(p)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var err error
+	if p.Created, err = decodeTimestamp("created", aux.Created); err != nil {
+		return err
+	}
+	if p.Modified, err = decodeTimestamp("modified", aux.Modified); err != nil {
+		return err
+	}
+	p.Extra, err = unknownFields(data, reflect.TypeOf(plain{}))
+	return err
+}
+
+// primaryServerRequest is the request body for creating and updating primary servers
+type primaryServerRequest struct {
+	Address string `json:"address"`
+	Port    int    `json:"port"`
+	ZoneID  string `json:"zone_id"`
+}
+
+// GetPrimaryServers retrieves the primary servers of a secondary zone. An
+// empty zoneID returns the primary servers of all zones.
+func (c *Client) GetPrimaryServers(zoneID string) ([]PrimaryServer, error) {
+	return c.GetPrimaryServersContext(context.Background(), zoneID)
+}
+
+// GetPrimaryServersContext is like GetPrimaryServers but uses ctx for the request
+func (c *Client) GetPrimaryServersContext(ctx context.Context, zoneID string) ([]PrimaryServer, error) {
+	path := "/primary_servers"
+	if zoneID != "" {
+		path += "?" + url.Values{"zone_id": {zoneID}}.Encode()
+	}
+
+	var result struct {
+		PrimaryServers []PrimaryServer `json:"primary_servers"`
+	}
+	if err := c.doJSON(ctx, "GET", path, nil, &result); err != nil {
+		return nil, err
+	}
+	return result.PrimaryServers, nil
+}
+
+// GetPrimaryServer retrieves a single primary server by its ID
+func (c *Client) GetPrimaryServer(id string) (*PrimaryServer, error) {
+	return c.GetPrimaryServerContext(context.Background(), id)
+}
+
+// GetPrimaryServerContext is like GetPrimaryServer but uses ctx for the request
+func (c *Client) GetPrimaryServerContext(ctx context.Context, id string) (*PrimaryServer, error) {
+	return c.doPrimaryServerRequest(ctx, "GET", fmt.Sprintf("/primary_servers/%s", id), nil)
+}
+
+// CreatePrimaryServer adds a primary server to a secondary zone
+func (c *Client) CreatePrimaryServer(server PrimaryServer) (*PrimaryServer, error) {
+	return c.CreatePrimaryServerContext(context.Background(), server)
+}
+
+// CreatePrimaryServerContext is like CreatePrimaryServer but uses ctx for the request
+func (c *Client) CreatePrimaryServerContext(ctx context.Context, server PrimaryServer) (*PrimaryServer, error) {
+	return c.doPrimaryServerRequest(ctx, "POST", "/primary_servers", &primaryServerRequest{
+		Address: server.Address,
+		Port:    server.Port,
+		ZoneID:  server.ZoneID,
+	})
+}
+
+// UpdatePrimaryServer changes the address or port of a primary server,
+// identified by its ID
+func (c *Client) UpdatePrimaryServer(server PrimaryServer) (*PrimaryServer, error) {
+	return c.UpdatePrimaryServerContext(context.Background(), server)
+}
+
+// UpdatePrimaryServerContext is like UpdatePrimaryServer but uses ctx for the request
+func (c *Client) UpdatePrimaryServerContext(ctx context.Context, server PrimaryServer) (*PrimaryServer, error) {
+	return c.doPrimaryServerRequest(ctx, "PUT", fmt.Sprintf("/primary_servers/%s", server.ID), &primaryServerRequest{
+		Address: server.Address,
+		Port:    server.Port,
+		ZoneID:  server.ZoneID,
+	})
+}
+
+// DeletePrimaryServer removes a primary server from its zone
+func (c *Client) DeletePrimaryServer(id string) error {
+	return c.DeletePrimaryServerContext(context.Background(), id)
+}
+
+// DeletePrimaryServerContext is like DeletePrimaryServer but uses ctx for the request
+func (c *Client) DeletePrimaryServerContext(ctx context.Context, id string) error {
+	return c.doJSON(ctx, "DELETE", fmt.Sprintf("/primary_servers/%s", id), nil, nil)
+}
+
+// doPrimaryServerRequest sends a request whose response holds a single primary server
+func (c *Client) doPrimaryServerRequest(ctx context.Context, method, path string, payload interface{}) (*PrimaryServer, error) {
+	var result struct {
+		PrimaryServer *PrimaryServer `json:"primary_server"`
+	}
+	if err := c.doJSON(ctx, method, path, payload, &result); err != nil {
+		return nil, err
+	}
+	if result.PrimaryServer == nil {
+		return nil, &DecodeError{Path: "primary_server", Err: errMissingField}
+	}
+	return result.PrimaryServer, nil
+}
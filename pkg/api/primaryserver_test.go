@@ -0,0 +1,98 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetPrimaryServers(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/primary_servers" || r.URL.Query().Get("zone_id") != "zone1" {
+			t.Errorf("Unexpected request %s", r.URL)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"primary_servers":[{"id":"ps1","address":"192.0.2.53","port":53,"zone_id":"zone1",
+			"created":"2024-01-02 03:04:05.123 +0000 UTC","modified":"2024-01-02 03:04:05.123 +0000 UTC"}]}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	servers, err := client.GetPrimaryServers("zone1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(servers) != 1 {
+		t.Fatalf("Expected 1 primary server, got %d", len(servers))
+	}
+	ps := servers[0]
+	if ps.ID != "ps1" || ps.Address != "192.0.2.53" || ps.Port != 53 || ps.ZoneID != "zone1" {
+		t.Errorf("Primary server data doesn't match expected values: %+v", ps)
+	}
+	if ps.Created.IsZero() {
+		t.Error("Expected created timestamp to be parsed")
+	}
+}
+
+func TestCreatePrimaryServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.URL.Path != "/primary_servers" {
+			t.Errorf("Expected POST /primary_servers, got %s %s", r.Method, r.URL.Path)
+		}
+
+		var body primaryServerRequest
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Address != "192.0.2.53" || body.Port != 5353 || body.ZoneID != "zone1" {
+			t.Errorf("Unexpected request body %+v", body)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"primary_server":{"id":"ps1","address":"192.0.2.53","port":5353,"zone_id":"zone1"}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	ps, err := client.CreatePrimaryServer(PrimaryServer{Address: "192.0.2.53", Port: 5353, ZoneID: "zone1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ps.ID != "ps1" {
+		t.Errorf("Expected primary server ID 'ps1', got '%s'", ps.ID)
+	}
+}
+
+func TestUpdatePrimaryServer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PUT" || r.URL.Path != "/primary_servers/ps1" {
+			t.Errorf("Expected PUT /primary_servers/ps1, got %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"primary_server":{"id":"ps1","address":"192.0.2.54","port":53,"zone_id":"zone1"}}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	ps, err := client.UpdatePrimaryServer(PrimaryServer{ID: "ps1", Address: "192.0.2.54", Port: 53, ZoneID: "zone1"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if ps.Address != "192.0.2.54" {
+		t.Errorf("Expected updated address '192.0.2.54', got '%s'", ps.Address)
+	}
+}
+
+func TestDeletePrimaryServer(t *testing.T) {
+	server := setupTestServer(t, "/primary_servers/ps1", http.StatusOK, nil)
+	defer server.Close()
+
+	client := newTestClient(server)
+
+	if err := client.DeletePrimaryServer("ps1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
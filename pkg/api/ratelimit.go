@@ -0,0 +1,164 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiting how many requests a client sends per
+// second. It is safe for concurrent use, and a single RateLimiter can be shared
+// by several clients to throttle them together.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	stats  RateLimiterStats
+}
+
+// RateLimiterStats describes how much a RateLimiter has throttled requests
+type RateLimiterStats struct {
+	// Requests is the number of requests that passed the limiter
+	Requests int64
+	// Delayed is the number of requests that had to wait for a token
+	Delayed int64
+	// TotalWait is the time requests spent waiting for a token
+	TotalWait time.Duration
+}
+
+// NewRateLimiter creates a limiter allowing requestsPerSecond requests on
+// average, with bursts of up to burst requests. A burst below 1 is treated as 1.
+func NewRateLimiter(requestsPerSecond float64, burst int) *RateLimiter {
+	burst = max(burst, 1)
+	return &RateLimiter{
+		rate:   requestsPerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// WithRateLimiter makes the client wait for the limiter before sending each
+// request, including retries
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(o *clientOptions) {
+		o.rateLimiter = limiter
+	}
+}
+
+// Wait blocks until a request may be sent or ctx is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		l.mu.Lock()
+		l.stats.Requests++
+		l.mu.Unlock()
+		return nil
+	}
+
+	// Reserve a token now and sleep for as long as the bucket is in debt
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	l.tokens--
+	var wait time.Duration
+	if l.tokens < 0 {
+		wait = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+	l.mu.Unlock()
+
+	if err := sleepContext(ctx, wait); err != nil {
+		// Give the reserved token back so other requests are not delayed by it
+		l.mu.Lock()
+		l.tokens = min(l.burst, l.tokens+1)
+		l.mu.Unlock()
+		return err
+	}
+
+	l.mu.Lock()
+	l.stats.Requests++
+	if wait > 0 {
+		l.stats.Delayed++
+		l.stats.TotalWait += wait
+	}
+	l.mu.Unlock()
+
+	return nil
+}
+
+// Stats returns a snapshot of the limiter statistics
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
+
+// RateLimitStatus is the request quota reported by the API in the headers of
+// its most recent response
+type RateLimitStatus struct {
+	// Limit is the number of requests allowed in the current window
+	Limit int
+	// Remaining is the number of requests left in the current window
+	Remaining int
+	// Reset is when the current window ends, if reported by the API
+	Reset time.Time
+	// Updated is when the response carrying these values was received
+	Updated time.Time
+}
+
+// RateLimit returns the quota reported by the most recent API response. The
+// boolean is false if no response has reported a quota yet.
+func (c *Client) RateLimit() (RateLimitStatus, bool) {
+	c.rateLimitMu.Lock()
+	defer c.rateLimitMu.Unlock()
+	return c.rateLimit, !c.rateLimit.Updated.IsZero()
+}
+
+// updateRateLimit records the quota headers of a response, if present
+func (c *Client) updateRateLimit(header http.Header, now time.Time) {
+	status, ok := parseRateLimit(header, now)
+	if !ok {
+		return
+	}
+
+	c.rateLimitMu.Lock()
+	c.rateLimit = status
+	c.rateLimitMu.Unlock()
+}
+
+// parseRateLimit extracts the quota from the RateLimit-* headers or, failing
+// that, the X-RateLimit-* headers
+func parseRateLimit(header http.Header, now time.Time) (RateLimitStatus, bool) {
+	status := RateLimitStatus{Updated: now}
+
+	limit, okLimit := headerInt(header, "RateLimit-Limit", "X-RateLimit-Limit-Minute", "X-RateLimit-Limit")
+	remaining, okRemaining := headerInt(header, "RateLimit-Remaining", "X-RateLimit-Remaining-Minute", "X-RateLimit-Remaining")
+	if !okLimit && !okRemaining {
+		return status, false
+	}
+	status.Limit = limit
+	status.Remaining = remaining
+
+	if reset, ok := headerInt(header, "RateLimit-Reset"); ok {
+		status.Reset = now.Add(time.Duration(reset) * time.Second)
+	}
+
+	return status, true
+}
+
+// headerInt returns the first of the given headers that holds an integer
+func headerInt(header http.Header, keys ...string) (int, bool) {
+	for _, key := range keys {
+		// Some proxies join repeated headers, keep the first value
+		value, _, _ := strings.Cut(header.Get(key), ",")
+		if n, err := strconv.Atoi(strings.TrimSpace(value)); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterBurst(t *testing.T) {
+	limiter := NewRateLimiter(20, 3)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := limiter.Wait(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > 40*time.Millisecond {
+		t.Errorf("Expected the burst to pass without waiting, took %v", elapsed)
+	}
+
+	// The fourth request has to wait for a new token (50ms at 20/s)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	stats := limiter.Stats()
+	if stats.Requests != 4 {
+		t.Errorf("Expected 4 requests, got %d", stats.Requests)
+	}
+	if stats.Delayed != 1 || stats.TotalWait < 30*time.Millisecond {
+		t.Errorf("Expected one delayed request of about 50ms, got %+v", stats)
+	}
+}
+
+func TestRateLimiterConcurrent(t *testing.T) {
+	limiter := NewRateLimiter(100, 1)
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := limiter.Wait(context.Background()); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// 10 requests at 100/s with a burst of 1 take at least 90ms
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("Expected requests to be spread out, took only %v", elapsed)
+	}
+	if stats := limiter.Stats(); stats.Requests != 10 || stats.Delayed != 9 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestRateLimiterContextCancel(t *testing.T) {
+	limiter := NewRateLimiter(0.1, 1)
+	limiter.Wait(context.Background())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if stats := limiter.Stats(); stats.Requests != 1 {
+		t.Errorf("Expected the cancelled request not to be counted, got %+v", stats)
+	}
+}
+
+func TestClientUsesRateLimiter(t *testing.T) {
+	server := setupTestServer(t, "/zones", http.StatusOK, ZonesResponse{Zones: []Zone{}})
+	defer server.Close()
+
+	limiter := NewRateLimiter(1000, 10)
+	client := NewClient("test-token", WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithRateLimiter(limiter))
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetZones(); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if stats := limiter.Stats(); stats.Requests != 3 {
+		t.Errorf("Expected 3 requests through the limiter, got %d", stats.Requests)
+	}
+}
+
+func TestClientRateLimitHeaders(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Ratelimit-Limit-Minute", "300")
+		w.Header().Set("X-Ratelimit-Remaining-Minute", "299")
+		w.Header().Set("Ratelimit-Reset", "42")
+		w.Write([]byte(`{"zones":[]}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(server)
+	if _, ok := client.RateLimit(); ok {
+		t.Error("Expected no rate limit status before the first request")
+	}
+
+	before := time.Now()
+	if _, err := client.GetZones(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	status, ok := client.RateLimit()
+	if !ok {
+		t.Fatal("Expected a rate limit status after the first request")
+	}
+	if status.Limit != 300 || status.Remaining != 299 {
+		t.Errorf("Expected limit 300 and remaining 299, got %+v", status)
+	}
+	if status.Reset.Before(before.Add(41*time.Second)) || status.Reset.After(time.Now().Add(42*time.Second)) {
+		t.Errorf("Expected reset in 42s, got %v", status.Reset)
+	}
+}
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy controls how requests that failed with a transient error are retried.
+// Only idempotent requests (GET, HEAD, OPTIONS, PUT and DELETE) are retried
+// unless RetryNonIdempotent is set.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry; it doubles with every
+	// further attempt and is randomized by up to half to avoid bursts
+	BaseDelay time.Duration
+	// MaxDelay caps the wait before a single retry. If the API asks to wait
+	// longer through Retry-After, the request is not retried at all.
+	MaxDelay time.Duration
+	// RetryNonIdempotent also retries POST and PATCH requests, which may
+	// create duplicates if the first attempt reached the API
+	RetryNonIdempotent bool
+}
+
+// errBodyNotRewindable is returned when a request body cannot be sent again
+var errBodyNotRewindable = errors.New("request body cannot be rewound")
+
+// DefaultRetryPolicy is a retry policy suitable for interactive use
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 4,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    30 * time.Second,
+}
+
+// WithRetryPolicy makes the client retry requests that failed with a rate
+// limit, a transient server error or a network error. By default requests are
+// not retried.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *clientOptions) {
+		o.retryPolicy = policy
+	}
+}
+
+// do sends the request, retrying it according to the client's retry policy
+// and waiting for the rate limiter before every attempt. The caller is
+// responsible for closing the body of the returned response.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	policy := c.retryPolicy
+
+	for attempt := 1; ; attempt++ {
+		if c.rateLimiter != nil {
+			if err := c.rateLimiter.Wait(req.Context()); err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err == nil {
+			c.updateRateLimit(resp.Header, time.Now())
+		}
+
+		if attempt >= policy.MaxAttempts || !policy.allowsMethod(req.Method) {
+			return resp, err
+		}
+		if err == nil && !isRetryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+		if err != nil && req.Context().Err() != nil {
+			return nil, err
+		}
+
+		delay, ok := policy.delay(attempt, resp)
+		if !ok {
+			return resp, err
+		}
+
+		next, rewindErr := rewindRequest(req)
+		if rewindErr != nil {
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+
+		if err := sleepContext(req.Context(), delay); err != nil {
+			return nil, err
+		}
+		req = next
+	}
+}
+
+// allowsMethod reports whether requests with the given method may be retried
+func (p RetryPolicy) allowsMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return p.RetryNonIdempotent
+}
+
+// delay returns how long to wait before the next attempt, preferring the
+// wait requested by the API. It returns false if the requested wait exceeds
+// MaxDelay.
+func (p RetryPolicy) delay(attempt int, resp *http.Response) (time.Duration, bool) {
+	if resp != nil {
+		if wait, ok := retryAfter(resp.StatusCode, resp.Header, time.Now()); ok {
+			if p.MaxDelay > 0 && wait > p.MaxDelay {
+				return 0, false
+			}
+			return wait, true
+		}
+	}
+
+	backoff := p.BaseDelay << (attempt - 1)
+	if backoff <= 0 || (p.MaxDelay > 0 && backoff > p.MaxDelay) {
+		backoff = p.MaxDelay
+	}
+	if backoff <= 0 {
+		return 0, true
+	}
+
+	// Randomize within [backoff/2, backoff) so concurrent clients spread out
+	half := backoff / 2
+	return half + rand.N(backoff-half), true
+}
+
+// isRetryableStatus reports whether a response status indicates a transient failure
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// retryAfter extracts the wait requested by the Retry-After header of a 429
+// or 503 response, given in seconds or as an HTTP date, or by the
+// RateLimit-Reset header of a 429 response, given in seconds. Other failures
+// carry the quota headers of every response and use the backoff instead.
+func retryAfter(statusCode int, header http.Header, now time.Time) (time.Duration, bool) {
+	if statusCode != http.StatusTooManyRequests && statusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	if value := strings.TrimSpace(header.Get("Retry-After")); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+		if date, err := http.ParseTime(value); err == nil {
+			return max(date.Sub(now), 0), true
+		}
+	}
+
+	if statusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	if value := strings.TrimSpace(header.Get("RateLimit-Reset")); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second, true
+		}
+	}
+
+	return 0, false
+}
+
+// rewindRequest returns a copy of req that can be sent again, with a fresh body
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return next, nil
+	}
+	if req.GetBody == nil {
+		return nil, errBodyNotRewindable
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	next.Body = body
+	return next, nil
+}
+
+// sleepContext waits for the given duration or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,193 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// setupSequenceServer returns a test server answering with the given status
+// codes in order, and 200 with an empty zone list once they are used up
+func setupSequenceServer(t *testing.T, header http.Header, statusCodes ...int) (*httptest.Server, *int, *[]string) {
+	calls := 0
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		calls++
+
+		for key, values := range header {
+			w.Header()[key] = values
+		}
+		if calls <= len(statusCodes) {
+			w.WriteHeader(statusCodes[calls-1])
+			w.Write([]byte(`{"error":{"message":"try again"}}`))
+			return
+		}
+		w.Write([]byte(`{"zones":[],"record":{"id":"record1"}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls, &bodies
+}
+
+func newRetryClient(server *httptest.Server, policy RetryPolicy) *Client {
+	return NewClient("test-token", WithBaseURL(server.URL), WithHTTPClient(server.Client()), WithRetryPolicy(policy))
+}
+
+func TestRetryTransientErrors(t *testing.T) {
+	server, calls, _ := setupSequenceServer(t, nil, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	client := newRetryClient(server, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 10 * time.Millisecond})
+
+	if _, err := client.GetZones(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if *calls != 3 {
+		t.Errorf("Expected 3 calls, got %d", *calls)
+	}
+}
+
+func TestRetryGivesUpAfterMaxAttempts(t *testing.T) {
+	server, calls, _ := setupSequenceServer(t, nil, http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway)
+	client := newRetryClient(server, RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond})
+
+	_, err := client.GetZones()
+	if !errors.Is(err, ErrServer) {
+		t.Fatalf("Expected ErrServer, got %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", *calls)
+	}
+}
+
+func TestRetryDisabledByDefault(t *testing.T) {
+	server, calls, _ := setupSequenceServer(t, nil, http.StatusServiceUnavailable)
+	client := newTestClient(server)
+
+	if _, err := client.GetZones(); !errors.Is(err, ErrServer) {
+		t.Fatalf("Expected ErrServer, got %v", err)
+	}
+	if *calls != 1 {
+		t.Errorf("Expected 1 call, got %d", *calls)
+	}
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	record := Record{Type: "A", Name: "www", Value: "192.0.2.1", ZoneID: "zone1"}
+
+	// POST requests are not retried by default
+	server, calls, _ := setupSequenceServer(t, nil, http.StatusTooManyRequests)
+	client := newRetryClient(server, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond})
+	if _, err := client.CreateRecord(record); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Expected ErrRateLimited, got %v", err)
+	}
+	if *calls != 1 {
+		t.Errorf("Expected 1 call, got %d", *calls)
+	}
+
+	// With RetryNonIdempotent the request is sent again with the same body
+	server, calls, bodies := setupSequenceServer(t, nil, http.StatusTooManyRequests)
+	client = newRetryClient(server, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryNonIdempotent: true})
+	if _, err := client.CreateRecord(record); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", *calls)
+	}
+	if (*bodies)[0] == "" || (*bodies)[0] != (*bodies)[1] {
+		t.Errorf("Expected the same body on both attempts, got %q and %q", (*bodies)[0], (*bodies)[1])
+	}
+}
+
+func TestRetryHonorsRetryAfter(t *testing.T) {
+	// A wait longer than MaxDelay is not retried
+	server, calls, _ := setupSequenceServer(t, http.Header{"Retry-After": {"120"}}, http.StatusTooManyRequests)
+	client := newRetryClient(server, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second})
+	if _, err := client.GetZones(); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Expected ErrRateLimited, got %v", err)
+	}
+	if *calls != 1 {
+		t.Errorf("Expected 1 call, got %d", *calls)
+	}
+
+	// A zero wait is retried immediately
+	server, calls, _ = setupSequenceServer(t, http.Header{"Retry-After": {"0"}}, http.StatusTooManyRequests)
+	client = newRetryClient(server, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour, MaxDelay: time.Hour})
+	if _, err := client.GetZones(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", *calls)
+	}
+}
+
+func TestRetryIgnoresRateLimitResetOnServerErrors(t *testing.T) {
+	// The quota headers of a 503 must not make the client wait for the
+	// rate limit window, which here is longer than MaxDelay
+	server, calls, _ := setupSequenceServer(t, http.Header{"Ratelimit-Reset": {"120"}}, http.StatusServiceUnavailable)
+	client := newRetryClient(server, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Second})
+	if _, err := client.GetZones(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if *calls != 2 {
+		t.Errorf("Expected 2 calls, got %d", *calls)
+	}
+}
+
+func TestRetryStopsOnContextCancel(t *testing.T) {
+	server, _, _ := setupSequenceServer(t, nil, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	client := newRetryClient(server, RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour, MaxDelay: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.GetZonesContext(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Errorf("Expected retry wait to be interrupted by the context")
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		status int
+		header http.Header
+		want   time.Duration
+		ok     bool
+	}{
+		{http.StatusTooManyRequests, http.Header{"Retry-After": {"5"}}, 5 * time.Second, true},
+		{http.StatusTooManyRequests, http.Header{"Retry-After": {now.Add(10 * time.Second).Format(http.TimeFormat)}}, 10 * time.Second, true},
+		{http.StatusTooManyRequests, http.Header{"Ratelimit-Reset": {"7"}}, 7 * time.Second, true},
+		{http.StatusTooManyRequests, http.Header{"Retry-After": {"soon"}}, 0, false},
+		{http.StatusTooManyRequests, http.Header{}, 0, false},
+		{http.StatusServiceUnavailable, http.Header{"Retry-After": {"5"}}, 5 * time.Second, true},
+		{http.StatusServiceUnavailable, http.Header{"Ratelimit-Reset": {"7"}}, 0, false},
+		{http.StatusInternalServerError, http.Header{"Retry-After": {"5"}, "Ratelimit-Reset": {"7"}}, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := retryAfter(tt.status, tt.header, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("retryAfter(%d, %v) = %v, %v; want %v, %v", tt.status, tt.header, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: 300 * time.Millisecond}
+
+	for attempt, want := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond} {
+		got, ok := policy.delay(attempt+1, nil)
+		if !ok || got < want/2 || got >= want {
+			t.Errorf("Attempt %d: expected delay in [%v, %v), got %v", attempt+1, want/2, want, got)
+		}
+	}
+}
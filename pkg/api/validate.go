@@ -0,0 +1,351 @@
+package api
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// RecordValueError is returned by ValidateRecordValue for a value that is
+// malformed for its record type. It matches ErrValidation with errors.Is.
+type RecordValueError struct {
+	Type   string
+	Value  string
+	Reason string
+}
+
+// Error implements the error interface
+func (e *RecordValueError) Error() string {
+	return fmt.Sprintf("invalid %s record value %q: %s", e.Type, e.Value, e.Reason)
+}
+
+// Is reports whether target is ErrValidation
+func (e *RecordValueError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// recordValueValidators check the value of a record type and return why it
+// is invalid, or an empty string
+var recordValueValidators = map[string]func(value string) string{
+	"A":     validateA,
+	"AAAA":  validateAAAA,
+	"CNAME": validateHostnameValue,
+	"NS":    validateHostnameValue,
+	"PTR":   validateHostnameValue,
+	"MX":    validateMX,
+	"TXT":   validateTXT,
+	"SRV":   validateSRV,
+	"CAA":   validateCAA,
+	"TLSA":  validateTLSA,
+	"DS":    validateDS,
+	"SOA":   validateSOA,
+	"HINFO": validateHINFO,
+}
+
+// ValidateRecordValue checks that value is well-formed for the record type,
+// using the presentation format of the API, e.g. "10 mail.example.com" for
+// MX records. Values of record types it does not know are accepted.
+func ValidateRecordValue(recordType, value string) error {
+	recordType = strings.ToUpper(recordType)
+	validate, ok := recordValueValidators[recordType]
+	if !ok {
+		return nil
+	}
+	if strings.TrimSpace(value) == "" {
+		return &RecordValueError{Type: recordType, Value: value, Reason: "value is empty"}
+	}
+	if reason := validate(value); reason != "" {
+		return &RecordValueError{Type: recordType, Value: value, Reason: reason}
+	}
+	return nil
+}
+
+// ValidateRecord checks the value of record with ValidateRecordValue
+func ValidateRecord(record Record) error {
+	return ValidateRecordValue(record.Type, record.Value)
+}
+
+func validateA(value string) string {
+	ip := net.ParseIP(value)
+	if ip == nil || ip.To4() == nil || strings.Contains(value, ":") {
+		return "not an IPv4 address, e.g. 192.0.2.1"
+	}
+	return ""
+}
+
+func validateAAAA(value string) string {
+	ip := net.ParseIP(value)
+	if ip == nil || !strings.Contains(value, ":") {
+		return "not an IPv6 address, e.g. 2001:db8::1"
+	}
+	return ""
+}
+
+func validateHostnameValue(value string) string {
+	return checkHostname("", value)
+}
+
+// checkHostname returns why name is not a valid domain name. Relative names,
+// "@" for the zone apex and labels starting with an underscore are allowed.
+func checkHostname(field, name string) string {
+	prefix := ""
+	if field != "" {
+		prefix = field + ": "
+	}
+
+	if name == "@" {
+		return ""
+	}
+	trimmed := strings.TrimSuffix(name, ".")
+	if trimmed == "" {
+		return prefix + fmt.Sprintf("%q is not a host name", name)
+	}
+	if len(trimmed) > 253 {
+		return prefix + "host name is longer than 253 characters"
+	}
+	for _, label := range strings.Split(trimmed, ".") {
+		if label == "" || len(label) > 63 {
+			return prefix + fmt.Sprintf("%q is not a host name: labels must have 1 to 63 characters", name)
+		}
+		if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return prefix + fmt.Sprintf("%q is not a host name: labels must not start or end with a hyphen", name)
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+				return prefix + fmt.Sprintf("%q is not a host name: invalid character %q", name, r)
+			}
+		}
+	}
+	return ""
+}
+
+// checkUint returns why s is not an unsigned integer of at most max
+func checkUint(field, s string, max uint64) string {
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil || n > max {
+		return fmt.Sprintf("%s must be a number from 0 to %d, got %q", field, max, s)
+	}
+	return ""
+}
+
+// checkFields splits value into whitespace separated fields and returns why
+// it does not have the expected fields, given as names for the message
+func checkFields(value string, names ...string) ([]string, string) {
+	fields := strings.Fields(value)
+	if len(fields) != len(names) {
+		return nil, fmt.Sprintf("expected %d fields (%s), got %d", len(names), strings.Join(names, " "), len(fields))
+	}
+	return fields, ""
+}
+
+func validateMX(value string) string {
+	fields, reason := checkFields(value, "priority", "mail-server")
+	if reason != "" {
+		return reason
+	}
+	if reason := checkUint("priority", fields[0], 65535); reason != "" {
+		return reason
+	}
+	// A single dot is the null MX of a domain that receives no mail
+	if fields[1] == "." {
+		return ""
+	}
+	return checkHostname("mail server", fields[1])
+}
+
+func validateTXT(value string) string {
+	if !strings.HasPrefix(strings.TrimSpace(value), `"`) {
+		return ""
+	}
+	strs, err := splitQuoted(value)
+	if err != "" {
+		return err
+	}
+	for _, s := range strs {
+		if len(s) > 255 {
+			return "quoted strings are limited to 255 characters, split longer text into several strings"
+		}
+	}
+	return ""
+}
+
+func validateSRV(value string) string {
+	fields, reason := checkFields(value, "priority", "weight", "port", "target")
+	if reason != "" {
+		return reason
+	}
+	for i, name := range []string{"priority", "weight", "port"} {
+		if reason := checkUint(name, fields[i], 65535); reason != "" {
+			return reason
+		}
+	}
+	if fields[3] == "." {
+		return ""
+	}
+	return checkHostname("target", fields[3])
+}
+
+func validateCAA(value string) string {
+	// The value is the rest after flags and tag and may contain spaces
+	fields := strings.Fields(value)
+	if len(fields) < 3 {
+		return `expected 3 fields (flags tag value), e.g. 0 issue "letsencrypt.org"`
+	}
+	if reason := checkUint("flags", fields[0], 255); reason != "" {
+		return reason
+	}
+	tag := fields[1]
+	if len(tag) > 15 {
+		return fmt.Sprintf("tag %q must have 1 to 15 characters", tag)
+	}
+	for _, r := range tag {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return fmt.Sprintf("tag %q must only contain letters and digits", tag)
+		}
+	}
+
+	rest := strings.TrimSpace(strings.TrimSpace(value)[len(fields[0]):])
+	caaValue := strings.TrimSpace(rest[len(tag):])
+	if strings.HasPrefix(caaValue, `"`) {
+		strs, reason := splitQuoted(caaValue)
+		if reason != "" {
+			return reason
+		}
+		if len(strs) != 1 {
+			return "value must be a single quoted string"
+		}
+		caaValue = strs[0]
+	}
+	if strings.EqualFold(tag, "iodef") && !strings.HasPrefix(caaValue, "mailto:") &&
+		!strings.HasPrefix(caaValue, "http://") && !strings.HasPrefix(caaValue, "https://") {
+		return "iodef value must be a mailto:, http:// or https:// URL"
+	}
+	return ""
+}
+
+func validateTLSA(value string) string {
+	fields, reason := checkFields(value, "usage", "selector", "matching-type", "certificate-data")
+	if reason != "" {
+		return reason
+	}
+	for i, field := range []struct {
+		name string
+		max  uint64
+	}{{"usage", 3}, {"selector", 1}, {"matching type", 2}} {
+		if reason := checkUint(field.name, fields[i], field.max); reason != "" {
+			return reason
+		}
+	}
+
+	digestLengths := map[string]int{"1": 32, "2": 64}
+	return checkHex("certificate data", fields[3], digestLengths[fields[2]])
+}
+
+func validateDS(value string) string {
+	fields, reason := checkFields(value, "key-tag", "algorithm", "digest-type", "digest")
+	if reason != "" {
+		return reason
+	}
+	if reason := checkUint("key tag", fields[0], 65535); reason != "" {
+		return reason
+	}
+	if reason := checkUint("algorithm", fields[1], 255); reason != "" {
+		return reason
+	}
+	if reason := checkUint("digest type", fields[2], 255); reason != "" {
+		return reason
+	}
+
+	digestLengths := map[string]int{"1": 20, "2": 32, "4": 48}
+	return checkHex("digest", fields[3], digestLengths[fields[2]])
+}
+
+// checkHex returns why s is not hexadecimal data of size bytes, or of any
+// size if size is zero
+func checkHex(field, s string, size int) string {
+	data, err := hex.DecodeString(s)
+	if err != nil || len(data) == 0 {
+		return fmt.Sprintf("%s must be hexadecimal", field)
+	}
+	if size > 0 && len(data) != size {
+		return fmt.Sprintf("%s must be %d hexadecimal characters for this type, got %d", field, size*2, len(s))
+	}
+	return ""
+}
+
+func validateSOA(value string) string {
+	fields, reason := checkFields(value, "primary-ns", "mailbox", "serial", "refresh", "retry", "expire", "minimum")
+	if reason != "" {
+		return reason
+	}
+	if reason := checkHostname("primary name server", fields[0]); reason != "" {
+		return reason
+	}
+	if reason := checkHostname("mailbox", fields[1]); reason != "" {
+		return reason
+	}
+	for i, name := range []string{"serial", "refresh", "retry", "expire", "minimum"} {
+		if reason := checkUint(name, fields[i+2], 1<<32-1); reason != "" {
+			return reason
+		}
+	}
+	return ""
+}
+
+func validateHINFO(value string) string {
+	var fields []string
+	if strings.Contains(value, `"`) {
+		strs, reason := splitQuoted(value)
+		if reason != "" {
+			return reason
+		}
+		fields = strs
+	} else {
+		fields = strings.Fields(value)
+	}
+	if len(fields) != 2 {
+		return fmt.Sprintf(`expected 2 fields (cpu os), got %d; quote fields containing spaces, e.g. "Intel Xeon" "Linux"`, len(fields))
+	}
+	return ""
+}
+
+// splitQuoted splits a sequence of quoted strings like `"a" "b c"`, returning
+// the unquoted strings or why value is not such a sequence
+func splitQuoted(value string) ([]string, string) {
+	var strs []string
+	rest := strings.TrimSpace(value)
+	for rest != "" {
+		if rest[0] != '"' {
+			return nil, "text outside of quotes, quote every string"
+		}
+
+		var s strings.Builder
+		closed := false
+		i := 1
+		for ; i < len(rest); i++ {
+			if rest[i] == '\\' && i+1 < len(rest) {
+				i++
+				s.WriteByte(rest[i])
+				continue
+			}
+			if rest[i] == '"' {
+				closed = true
+				break
+			}
+			s.WriteByte(rest[i])
+		}
+		if !closed {
+			return nil, "unbalanced quotes"
+		}
+
+		strs = append(strs, s.String())
+		rest = rest[i+1:]
+		if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+			return nil, "quoted strings must be separated by spaces"
+		}
+		rest = strings.TrimSpace(rest)
+	}
+	return strs, ""
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateRecordValue(t *testing.T) {
+	tests := []struct {
+		recordType string
+		value      string
+		wantErr    string
+	}{
+		{"A", "192.0.2.1", ""},
+		{"A", "2001:db8::1", "not an IPv4 address"},
+		{"A", "192.0.2", "not an IPv4 address"},
+		{"AAAA", "2001:db8::1", ""},
+		{"AAAA", "192.0.2.1", "not an IPv6 address"},
+		{"aaaa", "2001:db8::g", "not an IPv6 address"},
+		{"CNAME", "www.example.com.", ""},
+		{"CNAME", "www", ""},
+		{"CNAME", "bad..example.com", "labels must have 1 to 63 characters"},
+		{"NS", "-ns.example.com", "must not start or end with a hyphen"},
+		{"PTR", "host.example.com", ""},
+		{"MX", "10 mail.example.com.", ""},
+		{"MX", "0 .", ""},
+		{"MX", "mail.example.com", "expected 2 fields (priority mail-server)"},
+		{"MX", "70000 mail.example.com", "priority must be a number from 0 to 65535"},
+		{"TXT", "v=spf1 -all", ""},
+		{"TXT", `"part one" "part two"`, ""},
+		{"TXT", `"unterminated`, "unbalanced quotes"},
+		{"TXT", `"` + strings.Repeat("a", 256) + `"`, "limited to 255 characters"},
+		{"SRV", "10 5 5060 sip.example.com.", ""},
+		{"SRV", "10 5 sip.example.com.", "expected 4 fields"},
+		{"SRV", "10 5 99999 sip.example.com.", "port must be a number"},
+		{"CAA", `0 issue "letsencrypt.org"`, ""},
+		{"CAA", `0 iodef "mailto:security@example.com"`, ""},
+		{"CAA", `0 iodef "security@example.com"`, "iodef value must be"},
+		{"CAA", `0  issue "letsencrypt.org"`, ""},
+		{"CAA", "0\tissue \"letsencrypt.org; validationmethods=dns-01\"", ""},
+		{"CAA", `0 issue`, "expected 3 fields"},
+		{"CAA", `256 issue "letsencrypt.org"`, "flags must be a number from 0 to 255"},
+		{"TLSA", "3 1 1 " + strings.Repeat("ab", 32), ""},
+		{"TLSA", "3 1 1 abcd", "must be 64 hexadecimal characters"},
+		{"TLSA", "4 1 1 " + strings.Repeat("ab", 32), "usage must be a number from 0 to 3"},
+		{"DS", "12345 13 2 " + strings.Repeat("0f", 32), ""},
+		{"DS", "12345 13 2 xyz", "digest must be hexadecimal"},
+		{"SOA", "hydrogen.ns.hetzner.com. dns.hetzner.com. 2024010101 86400 10800 3600000 3600", ""},
+		{"SOA", "hydrogen.ns.hetzner.com. dns.hetzner.com. 2024010101 86400", "expected 7 fields"},
+		{"HINFO", `"Intel Xeon" "Linux"`, ""},
+		{"HINFO", "x86 Linux", ""},
+		{"HINFO", "Linux", "expected 2 fields (cpu os)"},
+		{"MX", "", "value is empty"},
+		{"RP", "anything goes", ""},
+	}
+
+	for _, tt := range tests {
+		err := ValidateRecordValue(tt.recordType, tt.value)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s %q: expected no error, got %v", tt.recordType, tt.value, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s %q: expected error containing %q, got %v", tt.recordType, tt.value, tt.wantErr, err)
+			continue
+		}
+		if !errors.Is(err, ErrValidation) {
+			t.Errorf("%s %q: expected the error to match ErrValidation", tt.recordType, tt.value)
+		}
+	}
+}
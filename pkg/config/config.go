@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -11,6 +12,10 @@ import (
 // Config holds the configuration for the application
 type Config struct {
 	APIToken string
+	// Endpoint overrides the Hetzner DNS API base URL when non-empty
+	Endpoint string
+	// Debug logs every API request and response to stderr
+	Debug bool
 }
 
 // Default config paths
@@ -19,15 +24,29 @@ var (
 	configFile string
 )
 
+// Notices receives informational messages, such as a missing config file. It
+// defaults to stderr to keep machine-readable output on stdout intact.
+var Notices io.Writer = os.Stderr
+
+// Dir returns the directory holding the config file and other state of the
+// application
+func Dir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error getting home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".config", "hetznerdns"), nil
+}
+
 // LoadConfig loads the configuration from the config file or environment variables
 func LoadConfig() (*Config, error) {
 	// Set default config file paths
-	homeDir, err := os.UserHomeDir()
+	dir, err := Dir()
 	if err != nil {
-		return nil, fmt.Errorf("error getting home directory: %w", err)
+		return nil, err
 	}
 
-	configDir = filepath.Join(homeDir, ".config", "hetznerdns")
+	configDir = dir
 	configFile = filepath.Join(configDir, "config.yaml")
 
 	// Create config directory if it doesn't exist
@@ -47,13 +66,15 @@ func LoadConfig() (*Config, error) {
 
 	// Set default values
 	viper.SetDefault("api_token", "")
+	viper.SetDefault("endpoint", "")
+	viper.SetDefault("debug", false)
 
 	// Read config file
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			// Only return an error if it's not a ConfigFileNotFoundError
 			// This makes it friendlier when the config file doesn't exist yet
-			fmt.Printf("Notice: Config file not found, will create a new one when you save settings.\n")
+			fmt.Fprintf(Notices, "Notice: Config file not found, will create a new one when you save settings.\n")
 		}
 		// Config file not found, will use defaults and env vars
 	}
@@ -61,6 +82,8 @@ func LoadConfig() (*Config, error) {
 	// Create config struct
 	config := &Config{
 		APIToken: viper.GetString("api_token"),
+		Endpoint: viper.GetString("endpoint"),
+		Debug:    viper.GetBool("debug"),
 	}
 
 	return config, nil
@@ -92,7 +115,7 @@ func SaveConfig(config *Config) error {
 		if err := os.WriteFile(configFile, []byte(content), 0600); err != nil {
 			return fmt.Errorf("error writing config file: %w", err)
 		}
-		fmt.Printf("Created config file at: %s\n", configFile)
+		fmt.Fprintf(Notices, "Created config file at: %s\n", configFile)
 	}
 
 	return nil